@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/database"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/mailer"
+	repository_impl "github.com/faizalnurrozi/go-starter-kit/internal/repository/impl"
+	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
+	"github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
+
+	"gorm.io/gorm"
+)
+
+// loadConfig loads the configuration and sets up logging, which every
+// command needs.
+func loadConfig(flags *configFlags) (*config.Config, error) {
+	opts, err := flags.options()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := config.Load(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := logger.Setup(cfg.Log); err != nil {
+		return nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	return cfg, nil
+}
+
+// resources are the connections shared by every command that touches data.
+type resources struct {
+	db    *gorm.DB
+	cache cache.Cache
+}
+
+func openResources(cfg *config.Config) (*resources, error) {
+	db, err := database.Connect(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	appCache, err := cache.New(cfg)
+	if err != nil {
+		database.Close(db)
+		return nil, fmt.Errorf("failed to initialize cache: %w", err)
+	}
+
+	return &resources{db: db, cache: appCache}, nil
+}
+
+func (r *resources) Close() error {
+	return errors.Join(r.cache.Close(), database.Close(r.db))
+}
+
+// services is the service layer wired over shared resources.
+type services struct {
+	audit        interfaces.AuditService
+	verification interfaces.EmailVerificationService
+	user         interfaces.UserService
+	userAdmin    interfaces.UserAdminService
+	auth         interfaces.AuthService
+	mfa          interfaces.MFAService
+}
+
+func newServices(cfg *config.Config, r *resources) (*services, error) {
+	mail, err := mailer.New(cfg.Mail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize mailer: %w", err)
+	}
+
+	// Initialize repositories
+	userRepo := repository_impl.NewUserRepository(r.db)
+	recoveryCodeRepo := repository_impl.NewRecoveryCodeRepository(r.db)
+	auditRepo := repository_impl.NewAuditEventRepository(r.db)
+	transactor := repository_impl.NewTransactor(r.db)
+
+	// Initialize services
+	auditService := serviceimpl.NewAuditService(auditRepo)
+	verificationService := serviceimpl.NewEmailVerificationService(userRepo, r.cache, cfg.Cache, mail, transactor, auditService, cfg.JWT, cfg.Verification)
+
+	return &services{
+		audit:        auditService,
+		verification: verificationService,
+		user:         serviceimpl.NewUserService(userRepo, r.cache, cfg.Cache, verificationService, transactor, auditService),
+		userAdmin:    serviceimpl.NewUserAdminService(userRepo, r.cache, cfg.Cache, transactor, auditService),
+		auth:         serviceimpl.NewAuthService(userRepo, recoveryCodeRepo, cfg.JWT, cfg.MFA, cfg.Verification),
+		mfa:          serviceimpl.NewMFAService(userRepo, recoveryCodeRepo, transactor, auditService, cfg.MFA),
+	}, nil
+}
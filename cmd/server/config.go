@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+func newConfigCommand(flags *configFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the effective configuration",
+	}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "print",
+			Short: "Print the configuration after defaults and environment overrides, with secrets masked",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				opts, err := flags.options()
+				if err != nil {
+					return err
+				}
+				cfg, err := config.Read(opts...)
+				if err != nil {
+					return err
+				}
+
+				enc := yaml.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent(2)
+				if err := enc.Encode(config.Masked(cfg)); err != nil {
+					return err
+				}
+				return enc.Close()
+			},
+		},
+		&cobra.Command{
+			Use:   "validate",
+			Short: "Check the configuration and report every problem found",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				opts, err := flags.options()
+				if err != nil {
+					return err
+				}
+				cfg, err := config.Read(opts...)
+				if err != nil {
+					return err
+				}
+				if err := config.Validate(cfg); err != nil {
+					return fmt.Errorf("invalid configuration:\n%w", err)
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "Configuration is valid")
+				return nil
+			},
+		},
+	)
+	return cmd
+}
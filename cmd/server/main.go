@@ -1,118 +1,72 @@
 package main
 
 import (
-	"context"
-	"log"
+	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
-	"time"
+	"strings"
 
-	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/buildinfo"
 	"github.com/faizalnurrozi/go-starter-kit/internal/config"
-	"github.com/faizalnurrozi/go-starter-kit/internal/database"
-	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
-	"github.com/faizalnurrozi/go-starter-kit/internal/grpc"
-	"github.com/faizalnurrozi/go-starter-kit/internal/handler"
-	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
-	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
-	repository_impl "github.com/faizalnurrozi/go-starter-kit/internal/repository/impl"
-	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
 
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/spf13/cobra"
 )
 
 func main() {
-	// Load configuration
-	cfg := config.Load()
-
-	// Initialize logger
-	logger.Init(cfg.Log.Level)
-
-	// Initialize database
-	db, err := database.Connect(cfg)
-	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
 	}
+}
 
-	// Initialize cache
-	redis := cache.NewRedisClient(cfg)
-
-	// Initialize gRPC server
-	grpcServer := grpc.NewServer(cfg)
-	go grpcServer.Start()
-
-	// Initialize repositories
-	userRepo := repository_impl.NewUserRepository(db)
-
-	// Initialize services
-	userService := serviceimpl.NewUserService(userRepo, redis)
-
-	// Initialize handlers
-	userHandler := handler.NewUserHandler(userService)
-	healthHandler := handler.NewHealthHandler()
-
-	// Initialize Fiber app
-	app := fiber.New(fiber.Config{
-		ErrorHandler: middleware.ErrorHandler,
-	})
-
-	// Global middleware
-	app.Use(cors.New())
-	app.Use(middleware.Logger())
-
-	// Setup routes
-	setupRoutes(app, userHandler, healthHandler)
-
-	// Start server
-	go func() {
-		if err := app.Listen(":" + cfg.Server.Port); err != nil {
-			log.Fatal("Failed to start server:", err)
-		}
-	}()
-
-	// Graceful shutdown
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
-
-	logger.Info("Shutting down server...")
-
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := app.ShutdownWithContext(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+func newRootCommand() *cobra.Command {
+	flags := &configFlags{}
+	opts := serveOptions{}
+	root := &cobra.Command{
+		Use:   "server",
+		Short: "Run the API servers and manage a deployment",
+		// Without a subcommand the binary serves, as it always has
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(flags, opts)
+		},
+		Version:       buildinfo.Get().String(),
+		SilenceUsage:  true,
+		SilenceErrors: false,
 	}
-
-	grpcServer.Stop()
-	database.Close(db)
-	redis.Close()
-
-	logger.Info("Server exited")
+	root.SetVersionTemplate("{{.Version}}\n")
+	root.PersistentFlags().StringVar(&flags.env, "env", "", "config profile to layer over config.yaml (default $APP_ENV)")
+	root.PersistentFlags().StringArrayVar(&flags.set, "set", nil, "override a config key, e.g. --set log.level=debug (repeatable)")
+
+	root.AddCommand(
+		newServeCommand(flags),
+		newMigrateCommand(flags),
+		newUserCommand(flags),
+		newConfigCommand(flags),
+	)
+	return root
 }
 
-func setupRoutes(app *fiber.App, userHandler *handler.UserHandler, healthHandler *handler.HealthHandler) {
-	// Health check
-	app.Get("/health", healthHandler.Check)
-
-	// API versioning
-	api := app.Group("/api")
-
-	// V1 Routes
-	v1 := api.Group("/v1")
+// configFlags are the root flags layered over the config files and the
+// environment.
+type configFlags struct {
+	env string
+	set []string
+}
 
-	// User routes
-	users := v1.Group("/users")
-	users.Use(middleware.Auth()) // Auth middleware
-	users.Get("/", userHandler.GetAll)
-	users.Post("/", middleware.ValidateRequest(&dto.CreateUserRequest{}), userHandler.Create)
-	users.Get("/:id", middleware.ValidateParams(), userHandler.GetByID)
-	users.Put("/:id", middleware.ValidateParams(), middleware.ValidateRequest(&dto.UpdateUserRequest{}), userHandler.Update)
-	users.Delete("/:id", middleware.ValidateParams(), userHandler.Delete)
+func (f *configFlags) options() ([]config.Option, error) {
+	var opts []config.Option
+	if f.env != "" {
+		opts = append(opts, config.WithProfile(f.env))
+	}
 
-	// V2 Routes (for future versions)
-	v2 := api.Group("/v2")
-	v2.Get("/users", userHandler.GetAll) // Same handler, different version
+	if len(f.set) > 0 {
+		overrides := make(map[string]string, len(f.set))
+		for _, pair := range f.set {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("--set %q: expected key=value", pair)
+			}
+			overrides[key] = value
+		}
+		opts = append(opts, config.WithOverrides(overrides))
+	}
+	return opts, nil
 }
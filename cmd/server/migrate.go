@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/database"
+
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand(flags *configFlags) *cobra.Command {
+	return &cobra.Command{
+		Use:   "migrate",
+		Short: "Create or update the database schema",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig(flags)
+			if err != nil {
+				return err
+			}
+
+			db, err := database.Connect(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			if err := database.Migrate(db); err != nil {
+				return errors.Join(fmt.Errorf("failed to migrate database: %w", err), database.Close(db))
+			}
+
+			log.Info(cmd.Context(), "Database migrated")
+			return database.Close(db)
+		},
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/handler"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+	"github.com/faizalnurrozi/go-starter-kit/internal/ratelimit"
+	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func setupRoutes(app *fiber.App, cfg *config.Config, appCache cache.Cache, limiter *ratelimit.Limiter, authz middleware.Authorizer, userHandler *handler.UserHandler, authHandler *handler.AuthHandler, mfaHandler *handler.MFAHandler, auditHandler *handler.AuditHandler, healthHandler *handler.HealthHandler) {
+	// Health check
+	app.Get("/health", healthHandler.Check)
+	app.Get("/livez", healthHandler.Live)
+	app.Get("/readyz", healthHandler.Ready)
+	app.Get("/version", healthHandler.Version)
+
+	// API versioning
+	api := app.Group("/api")
+
+	// Each API request passes one rate limit, after authentication where
+	// there is any so callers can be keyed by user
+	rateLimit := middleware.RateLimit(limiter)
+
+	// V1 Routes
+	v1 := api.Group("/v1")
+
+	// Auth routes
+	auth := v1.Group("/auth", rateLimit)
+	auth.Post("/login", middleware.ValidateRequest(&dto.LoginRequest{}), authHandler.Login)
+	auth.Post("/verify-email", middleware.ValidateRequest(&dto.VerifyEmailRequest{}), authHandler.VerifyEmail)
+	auth.Post("/verify-email/resend", middleware.ValidateRequest(&dto.ResendVerificationRequest{}), authHandler.ResendVerification)
+	auth.Post("/confirm-email-change", middleware.ValidateRequest(&dto.ConfirmEmailChangeRequest{}), authHandler.ConfirmEmailChange)
+	auth.Post("/mfa/verify", middleware.ValidateRequest(&dto.VerifyMFARequest{}), authHandler.VerifyMFA)
+	auth.Post("/mfa/totp/enroll", middleware.MFAEnrollmentAuth(cfg.JWT), mfaHandler.Enroll)
+	auth.Post("/mfa/totp/confirm", middleware.MFAEnrollmentAuth(cfg.JWT), middleware.ValidateRequest(&dto.TOTPCodeRequest{}), mfaHandler.Confirm)
+	auth.Delete("/mfa/totp", middleware.Auth(cfg.JWT), middleware.ValidateRequest(&dto.TOTPCodeRequest{}), mfaHandler.Disable)
+	auth.Post("/mfa/recovery-codes", middleware.Auth(cfg.JWT), middleware.ValidateRequest(&dto.TOTPCodeRequest{}), mfaHandler.RegenerateRecoveryCodes)
+
+	// Conditional GET for user reads, plus an optional shared cache for lists
+	userCache := middleware.HTTPCache(cfg.HTTPCache.UserCacheControl)
+	userListCache := []fiber.Handler{middleware.HTTPCache(cfg.HTTPCache.UserListCacheControl)}
+	if cfg.HTTPCache.ListCache {
+		ttl := time.Duration(cfg.HTTPCache.ListCacheTTL) * time.Second
+		userListCache = append(userListCache, middleware.ResponseCache(appCache, serviceimpl.UserListCacheGroup, ttl))
+	}
+
+	// User routes
+	users := v1.Group("/users")
+	users.Use(middleware.Auth(cfg.JWT), rateLimit) // Auth middleware
+	users.Get("/", append(userListCache, userHandler.GetAll)...)
+	// "me" routes must be registered before "/:id" so they are not parsed as an ID
+	users.Get("/me", userCache, userHandler.GetCurrent)
+	users.Patch("/me", middleware.ValidateRequest(&dto.UpdateCurrentUserRequest{}), userHandler.UpdateCurrent)
+	users.Delete("/me", userHandler.DeleteCurrent)
+	users.Post("/", middleware.ValidateRequest(&dto.CreateUserRequest{}), userHandler.Create)
+	users.Get("/:id", userCache, middleware.ValidateParams(), userHandler.GetByID)
+	users.Put("/:id", middleware.ValidateParams(), middleware.ValidateRequest(&dto.UpdateUserRequest{}), userHandler.Update)
+	users.Delete("/:id", middleware.ValidateParams(), userHandler.Delete)
+	users.Delete("/:id/mfa", middleware.RequireRole(authz, entity.RoleAdmin), middleware.ValidateParams(), mfaHandler.Reset)
+
+	// Audit routes
+	auditEvents := v1.Group("/audit-events")
+	auditEvents.Use(middleware.Auth(cfg.JWT), middleware.RequireRole(authz, entity.RoleAdmin), rateLimit)
+	auditEvents.Get("/", middleware.ValidateQuery(&dto.ListAuditEventsRequest{}), auditHandler.List)
+
+	// V2 Routes (for future versions)
+	v2 := api.Group("/v2", rateLimit)
+	v2.Get("/users", userHandler.GetAll) // Same handler, different version
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/admin"
+	"github.com/faizalnurrozi/go-starter-kit/internal/buildinfo"
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/database"
+	"github.com/faizalnurrozi/go-starter-kit/internal/grpc"
+	"github.com/faizalnurrozi/go-starter-kit/internal/handler"
+	"github.com/faizalnurrozi/go-starter-kit/internal/health"
+	"github.com/faizalnurrozi/go-starter-kit/internal/lifecycle"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/metrics"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+	"github.com/faizalnurrozi/go-starter-kit/internal/ratelimit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/cobra"
+	"gorm.io/gorm"
+)
+
+var log = logger.Named("server")
+
+type serveOptions struct {
+	httpOnly bool
+	grpcOnly bool
+}
+
+func newServeCommand(flags *configFlags) *cobra.Command {
+	opts := serveOptions{}
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the HTTP and gRPC servers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return serve(flags, opts)
+		},
+	}
+	cmd.Flags().BoolVar(&opts.httpOnly, "http-only", false, "start only the HTTP server")
+	cmd.Flags().BoolVar(&opts.grpcOnly, "grpc-only", false, "start only the gRPC server")
+	cmd.MarkFlagsMutuallyExclusive("http-only", "grpc-only")
+	return cmd
+}
+
+// serve wires the application and blocks until SIGINT or SIGTERM, or until
+// a server fails.
+func serve(flags *configFlags, opts serveOptions) error {
+	cfg, err := loadConfig(flags)
+	if err != nil {
+		return err
+	}
+	watcher, err := watchConfig(flags, cfg)
+	if err != nil {
+		return err
+	}
+
+	info := buildinfo.Get()
+	log.Info(context.Background(), "Starting "+info.Service,
+		logger.String("version", info.Version),
+		logger.String("commit", info.Commit),
+		logger.String("build_time", info.BuildTime),
+		logger.String("go_version", info.GoVersion),
+	)
+
+	// Components stop in reverse order, so tracing, the database and the
+	// cache appended first are closed after the servers have drained
+	shutdownTimeout := time.Duration(cfg.Server.ShutdownTimeout) * time.Second
+	lc := lifecycle.New(shutdownTimeout)
+
+	// Initialize tracing
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+	lc.Append(lifecycle.Hook{Name: "tracing", OnStop: shutdownTracing})
+	// The lifecycle flushes spans once it runs; flush them on earlier
+	// returns too
+	running := false
+	defer func() {
+		if running {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Warn(ctx, "Failed to shut down tracing", logger.Err(err))
+		}
+	}()
+
+	// Initialize database and cache
+	res, err := openResources(cfg)
+	if err != nil {
+		return err
+	}
+	lc.Append(lifecycle.Hook{
+		Name:   "database",
+		OnStop: func(context.Context) error { return database.Close(res.db) },
+	})
+	lc.Append(lifecycle.Hook{
+		Name:   "cache",
+		OnStop: func(context.Context) error { return res.cache.Close() },
+	})
+
+	if cfg.Database.AutoMigrate {
+		if err := database.Migrate(res.db); err != nil {
+			return errors.Join(fmt.Errorf("failed to migrate database: %w", err), res.Close())
+		}
+	}
+
+	svc, err := newServices(cfg, res)
+	if err != nil {
+		return errors.Join(err, res.Close())
+	}
+
+	// Export connection pool and cache tier stats
+	if sqlDB, err := res.db.DB(); err == nil {
+		if err := metrics.RegisterDBStats(sqlDB, cfg.Database.Database); err != nil {
+			log.Warn(context.Background(), "Failed to register database metrics", logger.Err(err))
+		}
+	}
+	if _, ok := cache.StatsOf(res.cache); ok {
+		err := metrics.RegisterCacheStats(func() cache.Stats {
+			stats, _ := cache.StatsOf(res.cache)
+			return stats
+		})
+		if err != nil {
+			log.Warn(context.Background(), "Failed to register cache metrics", logger.Err(err))
+		}
+	}
+
+	checker := newHealthChecker(cfg, res.db, res.cache)
+
+	limiter, err := ratelimit.New(cfg.RateLimit, res.cache)
+	if err != nil {
+		return errors.Join(fmt.Errorf("failed to initialize rate limiting: %w", err), res.Close())
+	}
+	config.Subscribe(watcher, func(c *config.Config) config.RateLimitConfig { return c.RateLimit }, func(_, next config.RateLimitConfig) {
+		if err := limiter.Update(next); err != nil {
+			log.Warn(context.Background(), "Failed to apply reloaded rate limits", logger.Err(err))
+			return
+		}
+		log.Info(context.Background(), "Rate limits reloaded")
+	})
+
+	corsOrigins := middleware.NewCORSOrigins(cfg.CORS.AllowOrigins)
+	config.Subscribe(watcher, func(c *config.Config) []string { return c.CORS.AllowOrigins }, func(_, next []string) {
+		corsOrigins.Set(next)
+		log.Info(context.Background(), "CORS origins reloaded")
+	})
+
+	// Initialize admin server
+	if cfg.Admin.Enabled {
+		adminServer := admin.NewServer(cfg.Admin)
+		adminServer.Handle("/log/level", logger.LevelHandler())
+		lc.AppendServer("admin", adminServer)
+	}
+
+	// Initialize gRPC server
+	var grpcServer *grpc.Server
+	if !opts.httpOnly {
+		grpcServer = grpc.NewServer(cfg.GRPC, cfg.JWT, limiter, svc.user)
+		checker.Register("grpc", grpcServer.Check)
+		lc.AppendServer("grpc", grpcServer)
+	}
+
+	// Initialize HTTP server
+	if !opts.grpcOnly {
+		app := newHTTPApp(cfg, res.cache, limiter, corsOrigins, svc, checker)
+		lc.AppendServer("http", &httpServer{app: app, addr: ":" + cfg.Server.Port})
+	}
+
+	// Stopped first: fail readiness and keep serving for shutdown_delay so
+	// load balancers stop sending new requests before the servers drain
+	lc.Append(lifecycle.Hook{
+		Name: "readiness",
+		OnStop: func(ctx context.Context) error {
+			if grpcServer != nil {
+				grpcServer.Drain()
+			}
+			return checker.Drain(ctx, time.Duration(cfg.Server.ShutdownDelay)*time.Second)
+		},
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	running = true
+	if err := lc.Run(ctx); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "Server exited")
+	return nil
+}
+
+func newHTTPApp(cfg *config.Config, appCache cache.Cache, limiter *ratelimit.Limiter, corsOrigins *middleware.CORSOrigins, svc *services, checker *health.Checker) *fiber.App {
+	// Initialize handlers
+	userHandler := handler.NewUserHandler(svc.user)
+	authHandler := handler.NewAuthHandler(svc.auth, svc.verification)
+	mfaHandler := handler.NewMFAHandler(svc.mfa)
+	auditHandler := handler.NewAuditHandler(svc.audit)
+	healthHandler := handler.NewHealthHandler(checker, appCache)
+
+	// Initialize Fiber app
+	app := fiber.New(middleware.TrustedProxies(fiber.Config{
+		ErrorHandler: middleware.ErrorHandler,
+	}, cfg.Server))
+
+	// Global middleware
+	app.Use(middleware.Tracing())
+	app.Use(middleware.RequestID())
+	app.Use(middleware.Metrics())
+	app.Use(middleware.CORS(cfg.CORS, corsOrigins))
+	app.Use(middleware.Logger())
+	app.Use(middleware.AuditMetadata())
+
+	// Setup routes
+	setupRoutes(app, cfg, appCache, limiter, svc.auth, userHandler, authHandler, mfaHandler, auditHandler, healthHandler)
+	return app
+}
+
+// httpServer runs the Fiber app as a lifecycle.Server.
+type httpServer struct {
+	app      *fiber.App
+	addr     string
+	listener net.Listener
+}
+
+func (s *httpServer) Listen() error {
+	lis, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	s.listener = lis
+	return nil
+}
+
+func (s *httpServer) Serve() error {
+	return s.app.Listener(s.listener)
+}
+
+func (s *httpServer) Stop(ctx context.Context) error {
+	return s.app.ShutdownWithContext(ctx)
+}
+
+// newHealthChecker registers the dependencies readiness depends on. Redis is
+// optional when the cache may fail open.
+func newHealthChecker(cfg *config.Config, db *gorm.DB, appCache cache.Cache) *health.Checker {
+	checker := health.NewChecker(cfg.Health)
+
+	if sqlDB, err := db.DB(); err == nil {
+		checker.Register("database", sqlDB.PingContext)
+	}
+
+	if pinger, ok := cache.PingerOf(appCache); ok {
+		if cfg.Redis.FailOpen {
+			checker.RegisterOptional("redis", pinger.Ping)
+		} else {
+			checker.Register("redis", pinger.Ping)
+		}
+	}
+
+	return checker
+}
+
+// watchConfig reloads the configuration when its files change and applies
+// the settings that are safe to change while serving.
+func watchConfig(flags *configFlags, cfg *config.Config) (*config.Watcher, error) {
+	opts, err := flags.options()
+	if err != nil {
+		return nil, err
+	}
+	watcher := config.NewWatcher(cfg, opts...)
+
+	config.Subscribe(watcher, func(c *config.Config) config.LogConfig { return c.Log }, func(_, next config.LogConfig) {
+		if err := logger.SetLevels(next.Level, next.Levels); err != nil {
+			log.Warn(context.Background(), "Failed to apply reloaded log levels", logger.Err(err))
+			return
+		}
+		log.Info(context.Background(), "Log levels reloaded")
+	})
+
+	err = watcher.Start(func(err error) {
+		log.Warn(context.Background(), "Failed to reload config", logger.Err(err))
+	})
+	return watcher, err
+}
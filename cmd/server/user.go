@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	apperrors "github.com/faizalnurrozi/go-starter-kit/internal/errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/spf13/cobra"
+)
+
+func newUserCommand(flags *configFlags) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Manage user accounts",
+	}
+	cmd.AddCommand(newCreateAdminCommand(flags), newSetPasswordCommand(flags))
+	return cmd
+}
+
+func newCreateAdminCommand(flags *configFlags) *cobra.Command {
+	req := &dto.CreateUserRequest{}
+	cmd := &cobra.Command{
+		Use:   "create-admin",
+		Short: "Create an administrator account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readPassword(cmd, &req.Password); err != nil {
+				return err
+			}
+			return withServices(flags, func(ctx context.Context, svc *services) error {
+				user, err := svc.userAdmin.CreateAdmin(ctx, req)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Created admin %s (id %d)\n", user.Email, user.ID)
+				return nil
+			}, req)
+		},
+	}
+	cmd.Flags().StringVar(&req.Name, "name", "", "display name")
+	cmd.Flags().StringVar(&req.Email, "email", "", "email address")
+	cmd.Flags().StringVar(&req.Password, "password", "", "password; read from stdin when omitted")
+	cmd.MarkFlagRequired("name")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+func newSetPasswordCommand(flags *configFlags) *cobra.Command {
+	req := &dto.SetPasswordRequest{}
+	cmd := &cobra.Command{
+		Use:   "set-password",
+		Short: "Replace a user's password",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := readPassword(cmd, &req.Password); err != nil {
+				return err
+			}
+			return withServices(flags, func(ctx context.Context, svc *services) error {
+				if err := svc.userAdmin.SetPassword(ctx, req); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Password set for %s\n", req.Email)
+				return nil
+			}, req)
+		},
+	}
+	cmd.Flags().StringVar(&req.Email, "email", "", "email address of the user")
+	cmd.Flags().StringVar(&req.Password, "password", "", "new password; read from stdin when omitted")
+	cmd.MarkFlagRequired("email")
+	return cmd
+}
+
+// readPassword fills an empty password from the first line of stdin, so it
+// need not appear in the process list or shell history.
+func readPassword(cmd *cobra.Command, password *string) error {
+	if *password != "" {
+		return nil
+	}
+
+	fmt.Fprint(cmd.ErrOrStderr(), "Password: ")
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	*password = strings.TrimRight(line, "\r\n")
+	return nil
+}
+
+// withServices validates req, then runs fn against the configured database
+// and cache.
+func withServices(flags *configFlags, fn func(ctx context.Context, svc *services) error, req interface{}) error {
+	if err := validateInput(req); err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(flags)
+	if err != nil {
+		return err
+	}
+	res, err := openResources(cfg)
+	if err != nil {
+		return err
+	}
+	svc, err := newServices(cfg, res)
+	if err != nil {
+		return errors.Join(err, res.Close())
+	}
+
+	ctx := auth.WithPrincipal(context.Background(), auth.SystemPrincipal("cli"))
+	return errors.Join(fn(ctx, svc), res.Close())
+}
+
+// validateInput applies the same rules as the API's request validation.
+func validateInput(req interface{}) error {
+	err := validator.New().Struct(req)
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return err
+	}
+
+	var messages []string
+	for _, e := range verrs {
+		messages = append(messages, strings.ToLower(e.Field())+" "+e.Tag())
+	}
+	return apperrors.NewValidationError("Validation failed: " + strings.Join(messages, ", "))
+}
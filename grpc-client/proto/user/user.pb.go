@@ -333,6 +333,42 @@ func (x *ListUsersRequest) GetOffset() int32 {
 	return 0
 }
 
+type GetCurrentUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentUserRequest) Reset() {
+	*x = GetCurrentUserRequest{}
+	mi := &file_proto_user_user_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentUserRequest) ProtoMessage() {}
+
+func (x *GetCurrentUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_user_user_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentUserRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_user_user_proto_rawDescGZIP(), []int{6}
+}
+
 type UserResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            uint32                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -347,7 +383,7 @@ type UserResponse struct {
 
 func (x *UserResponse) Reset() {
 	*x = UserResponse{}
-	mi := &file_proto_user_user_proto_msgTypes[6]
+	mi := &file_proto_user_user_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -359,7 +395,7 @@ func (x *UserResponse) String() string {
 func (*UserResponse) ProtoMessage() {}
 
 func (x *UserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_user_proto_msgTypes[6]
+	mi := &file_proto_user_user_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -372,7 +408,7 @@ func (x *UserResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserResponse.ProtoReflect.Descriptor instead.
 func (*UserResponse) Descriptor() ([]byte, []int) {
-	return file_proto_user_user_proto_rawDescGZIP(), []int{6}
+	return file_proto_user_user_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *UserResponse) GetId() uint32 {
@@ -427,7 +463,7 @@ type ListUsersResponse struct {
 
 func (x *ListUsersResponse) Reset() {
 	*x = ListUsersResponse{}
-	mi := &file_proto_user_user_proto_msgTypes[7]
+	mi := &file_proto_user_user_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -439,7 +475,7 @@ func (x *ListUsersResponse) String() string {
 func (*ListUsersResponse) ProtoMessage() {}
 
 func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_user_user_proto_msgTypes[7]
+	mi := &file_proto_user_user_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -452,7 +488,7 @@ func (x *ListUsersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListUsersResponse.ProtoReflect.Descriptor instead.
 func (*ListUsersResponse) Descriptor() ([]byte, []int) {
-	return file_proto_user_user_proto_rawDescGZIP(), []int{7}
+	return file_proto_user_user_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ListUsersResponse) GetUsers() []*UserResponse {
@@ -495,7 +531,8 @@ const file_proto_user_user_proto_rawDesc = "" +
 	"\amessage\x18\x01 \x01(\tR\amessage\"@\n" +
 	"\x10ListUsersRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x02 \x01(\x05R\x06offset\"\xa3\x01\n" +
+	"\x06offset\x18\x02 \x01(\x05R\x06offset\"\x17\n" +
+	"\x15GetCurrentUserRequest\"\xa3\x01\n" +
 	"\fUserResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\rR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
@@ -507,7 +544,7 @@ const file_proto_user_user_proto_rawDesc = "" +
 	"updated_at\x18\x06 \x01(\tR\tupdatedAt\"S\n" +
 	"\x11ListUsersResponse\x12(\n" +
 	"\x05users\x18\x01 \x03(\v2\x12.user.UserResponseR\x05users\x12\x14\n" +
-	"\x05total\x18\x02 \x01(\x05R\x05total2\xb7\x02\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total2\xfa\x02\n" +
 	"\vUserService\x129\n" +
 	"\n" +
 	"CreateUser\x12\x17.user.CreateUserRequest\x1a\x12.user.UserResponse\x123\n" +
@@ -516,7 +553,8 @@ const file_proto_user_user_proto_rawDesc = "" +
 	"UpdateUser\x12\x17.user.UpdateUserRequest\x1a\x12.user.UserResponse\x12?\n" +
 	"\n" +
 	"DeleteUser\x12\x17.user.DeleteUserRequest\x1a\x18.user.DeleteUserResponse\x12<\n" +
-	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponseB\fZ\n" +
+	"\tListUsers\x12\x16.user.ListUsersRequest\x1a\x17.user.ListUsersResponse\x12A\n" +
+	"\x0eGetCurrentUser\x12\x1b.user.GetCurrentUserRequest\x1a\x12.user.UserResponseB\fZ\n" +
 	"proto/userb\x06proto3"
 
 var (
@@ -531,31 +569,34 @@ func file_proto_user_user_proto_rawDescGZIP() []byte {
 	return file_proto_user_user_proto_rawDescData
 }
 
-var file_proto_user_user_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_user_user_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_proto_user_user_proto_goTypes = []any{
-	(*CreateUserRequest)(nil),  // 0: user.CreateUserRequest
-	(*GetUserRequest)(nil),     // 1: user.GetUserRequest
-	(*UpdateUserRequest)(nil),  // 2: user.UpdateUserRequest
-	(*DeleteUserRequest)(nil),  // 3: user.DeleteUserRequest
-	(*DeleteUserResponse)(nil), // 4: user.DeleteUserResponse
-	(*ListUsersRequest)(nil),   // 5: user.ListUsersRequest
-	(*UserResponse)(nil),       // 6: user.UserResponse
-	(*ListUsersResponse)(nil),  // 7: user.ListUsersResponse
+	(*CreateUserRequest)(nil),     // 0: user.CreateUserRequest
+	(*GetUserRequest)(nil),        // 1: user.GetUserRequest
+	(*UpdateUserRequest)(nil),     // 2: user.UpdateUserRequest
+	(*DeleteUserRequest)(nil),     // 3: user.DeleteUserRequest
+	(*DeleteUserResponse)(nil),    // 4: user.DeleteUserResponse
+	(*ListUsersRequest)(nil),      // 5: user.ListUsersRequest
+	(*GetCurrentUserRequest)(nil), // 6: user.GetCurrentUserRequest
+	(*UserResponse)(nil),          // 7: user.UserResponse
+	(*ListUsersResponse)(nil),     // 8: user.ListUsersResponse
 }
 var file_proto_user_user_proto_depIdxs = []int32{
-	6, // 0: user.ListUsersResponse.users:type_name -> user.UserResponse
+	7, // 0: user.ListUsersResponse.users:type_name -> user.UserResponse
 	0, // 1: user.UserService.CreateUser:input_type -> user.CreateUserRequest
 	1, // 2: user.UserService.GetUser:input_type -> user.GetUserRequest
 	2, // 3: user.UserService.UpdateUser:input_type -> user.UpdateUserRequest
 	3, // 4: user.UserService.DeleteUser:input_type -> user.DeleteUserRequest
 	5, // 5: user.UserService.ListUsers:input_type -> user.ListUsersRequest
-	6, // 6: user.UserService.CreateUser:output_type -> user.UserResponse
-	6, // 7: user.UserService.GetUser:output_type -> user.UserResponse
-	6, // 8: user.UserService.UpdateUser:output_type -> user.UserResponse
-	4, // 9: user.UserService.DeleteUser:output_type -> user.DeleteUserResponse
-	7, // 10: user.UserService.ListUsers:output_type -> user.ListUsersResponse
-	6, // [6:11] is the sub-list for method output_type
-	1, // [1:6] is the sub-list for method input_type
+	6, // 6: user.UserService.GetCurrentUser:input_type -> user.GetCurrentUserRequest
+	7, // 7: user.UserService.CreateUser:output_type -> user.UserResponse
+	7, // 8: user.UserService.GetUser:output_type -> user.UserResponse
+	7, // 9: user.UserService.UpdateUser:output_type -> user.UserResponse
+	4, // 10: user.UserService.DeleteUser:output_type -> user.DeleteUserResponse
+	8, // 11: user.UserService.ListUsers:output_type -> user.ListUsersResponse
+	7, // 12: user.UserService.GetCurrentUser:output_type -> user.UserResponse
+	7, // [7:13] is the sub-list for method output_type
+	1, // [1:7] is the sub-list for method input_type
 	1, // [1:1] is the sub-list for extension type_name
 	1, // [1:1] is the sub-list for extension extendee
 	0, // [0:1] is the sub-list for field type_name
@@ -573,7 +614,7 @@ func file_proto_user_user_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_user_user_proto_rawDesc), len(file_proto_user_user_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
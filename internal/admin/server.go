@@ -0,0 +1,68 @@
+// Package admin serves operational endpoints such as metrics on a port
+// separate from the public API.
+package admin
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/metrics"
+)
+
+var log = logger.Named("admin")
+
+type Server struct {
+	server   *http.Server
+	mux      *http.ServeMux
+	config   config.AdminConfig
+	listener net.Listener
+}
+
+func NewServer(cfg config.AdminConfig) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(cfg.MetricsPath, metrics.Handler())
+
+	return &Server{
+		server: &http.Server{
+			Addr:              net.JoinHostPort(cfg.Host, cfg.Port),
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+		mux:    mux,
+		config: cfg,
+	}
+}
+
+// Handle registers an additional admin endpoint.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Listen binds the admin port, so a taken port fails startup.
+func (s *Server) Listen() error {
+	lis, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return err
+	}
+	s.listener = lis
+
+	log.Info(context.Background(), "Admin server listening", logger.String("addr", s.server.Addr))
+	return nil
+}
+
+// Serve blocks until the server stops; it returns nil after Stop.
+func (s *Server) Serve() error {
+	if err := s.server.Serve(s.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) Stop(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+)
+
+// Actor types recorded on audit events.
+const (
+	ActorUser      = "user"
+	ActorSystem    = "system"
+	ActorAnonymous = "anonymous"
+)
+
+// Target types recorded on audit events.
+const (
+	TargetUser = "user"
+)
+
+// Actions recorded on audit events.
+const (
+	ActionUserCreate        = "user.create"
+	ActionUserUpdate        = "user.update"
+	ActionUserDelete        = "user.delete"
+	ActionUserEmailVerified = "user.email_verified"
+	ActionUserEmailChanged  = "user.email_changed"
+	ActionUserMFAEnabled    = "user.mfa_enabled"
+	ActionUserMFADisabled   = "user.mfa_disabled"
+	ActionUserMFAReset      = "user.mfa_reset"
+	ActionUserRecoveryCodes = "user.recovery_codes_regenerated"
+	ActionUserPasswordSet   = "user.password_set"
+)
+
+type Actor struct {
+	Type string
+	ID   string
+}
+
+// Entry describes a single mutation to record. Before and After are
+// snapshots (see Snapshot); either may be nil for creations and deletions.
+// Actor defaults to the principal authenticated on the context.
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   uint
+	Before     map[string]interface{}
+	After      map[string]interface{}
+	Actor      *Actor
+}
+
+// ActorFromContext returns the authenticated principal as an actor, or an
+// anonymous actor when the call was not authenticated.
+func ActorFromContext(ctx context.Context) Actor {
+	if principal, ok := auth.PrincipalFromContext(ctx); ok {
+		if principal.System != "" {
+			return *SystemActor(principal.System)
+		}
+		return Actor{Type: ActorUser, ID: strconv.FormatUint(uint64(principal.UserID), 10)}
+	}
+	return Actor{Type: ActorAnonymous}
+}
+
+// UserActor identifies a user acting without an access token, e.g. through
+// a signed link sent by email.
+func UserActor(id uint) *Actor {
+	return &Actor{Type: ActorUser, ID: strconv.FormatUint(uint64(id), 10)}
+}
+
+// SystemActor identifies an operator tool, such as the command line, acting
+// outside any request.
+func SystemActor(id string) *Actor {
+	return &Actor{Type: ActorSystem, ID: id}
+}
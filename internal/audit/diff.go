@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Redacted replaces the value of secret fields in recorded changes.
+const Redacted = "[REDACTED]"
+
+// Fields containing any of these fragments are never recorded in clear.
+var secretFragments = []string{"password", "secret", "token", "recovery_code"}
+
+// Bookkeeping fields that change on every write and would only add noise.
+var ignoredFields = map[string]bool{
+	"updated_at": true,
+}
+
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Snapshot flattens the exported fields of a struct into a map keyed by the
+// field's JSON name, falling back to its snake_case name for fields hidden
+// from JSON (such as password hashes) so that changes to them are still
+// detected. Nil or non-struct values yield nil.
+func Snapshot(v interface{}) map[string]interface{} {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	rt := rv.Type()
+	snapshot := make(map[string]interface{}, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		snapshot[fieldName(field)] = normalize(rv.Field(i))
+	}
+	return snapshot
+}
+
+// Diff returns the fields whose value differs between before and after,
+// with secret fields redacted.
+func Diff(before, after map[string]interface{}) map[string]Change {
+	changes := make(map[string]Change)
+
+	keys := make(map[string]bool, len(before)+len(after))
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+
+	for key := range keys {
+		if ignoredFields[key] {
+			continue
+		}
+
+		b, inBefore := before[key]
+		a, inAfter := after[key]
+		if inBefore && inAfter && reflect.DeepEqual(b, a) {
+			continue
+		}
+		// Zero values on a created or deleted record carry no information
+		if isZero(b) && isZero(a) {
+			continue
+		}
+
+		if isSecret(key) {
+			change := Change{}
+			if inBefore && !isZero(b) {
+				change.Before = Redacted
+			}
+			if inAfter && !isZero(a) {
+				change.After = Redacted
+			}
+			changes[key] = change
+			continue
+		}
+
+		changes[key] = Change{Before: b, After: a}
+	}
+
+	return changes
+}
+
+func fieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" && tag != "-" {
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name
+		}
+	}
+	return snakeCase(field.Name)
+}
+
+// normalize converts values into forms that compare and serialise
+// predictably: nil pointers become nil and times are rendered in UTC.
+func normalize(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		if value.IsZero() {
+			return nil
+		}
+		return value.UTC().Format(time.RFC3339Nano)
+	}
+
+	// gorm.DeletedAt and similar wrappers expose a Time/Valid pair
+	if v.Kind() == reflect.Struct {
+		if valid := v.FieldByName("Valid"); valid.IsValid() && valid.Kind() == reflect.Bool {
+			if !valid.Bool() {
+				return nil
+			}
+			if t, ok := v.FieldByName("Time").Interface().(time.Time); ok {
+				return t.UTC().Format(time.RFC3339Nano)
+			}
+		}
+	}
+
+	return v.Interface()
+}
+
+func isSecret(key string) bool {
+	for _, fragment := range secretFragments {
+		if strings.Contains(key, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func isZero(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// Start a new word at a lower→upper boundary, or at the last
+			// capital of an acronym followed by a lowercase letter (TOTPSecret)
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
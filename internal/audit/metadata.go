@@ -0,0 +1,29 @@
+package audit
+
+import "context"
+
+type contextKey string
+
+// MetadataKey is the context key holding the request *Metadata. Like
+// auth.PrincipalKey it is set with WithMetadata, on c.UserContext() in Fiber
+// and on the call context in gRPC interceptors.
+const MetadataKey contextKey = "audit.metadata"
+
+// Metadata describes the request a mutation was made in.
+type Metadata struct {
+	RequestID string
+	IP        string
+	UserAgent string
+}
+
+func WithMetadata(ctx context.Context, metadata *Metadata) context.Context {
+	return context.WithValue(ctx, MetadataKey, metadata)
+}
+
+// MetadataFromContext returns the request metadata in ctx, or an empty value.
+func MetadataFromContext(ctx context.Context) Metadata {
+	if metadata, ok := ctx.Value(MetadataKey).(*Metadata); ok && metadata != nil {
+		return *metadata
+	}
+	return Metadata{}
+}
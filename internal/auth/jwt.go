@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Token purposes. Only access tokens grant access to the API; the MFA
+// purposes are short-lived tokens issued between the password and the
+// second-factor step of a login, and the email purposes are only accepted by
+// the matching confirmation endpoint.
+const (
+	PurposeAccess      = "access"
+	PurposeMFA         = "mfa"
+	PurposeMFAEnroll   = "mfa_enroll"
+	PurposeEmailVerify = "email_verify"
+	PurposeEmailChange = "email_change"
+)
+
+type Claims struct {
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email"`
+	Role    string `json:"role,omitempty"`
+	Purpose string `json:"purpose,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// GenerateToken signs claims with the HMAC secret and an expiry of ttl from now.
+func GenerateToken(secret string, claims Claims, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+	if claims.Purpose == "" {
+		claims.Purpose = PurposeAccess
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseToken validates the signature and expiry of tokenString. Tokens issued
+// before purposes were introduced carry none and are treated as access tokens.
+func ParseToken(secret, tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	if claims.Purpose == "" {
+		claims.Purpose = PurposeAccess
+	}
+	return claims, nil
+}
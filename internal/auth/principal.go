@@ -0,0 +1,43 @@
+package auth
+
+import "context"
+
+type contextKey string
+
+// PrincipalKey is the context key holding the authenticated *Principal. The
+// Fiber middleware stores it in c.UserContext() and the gRPC interceptor in
+// the call context, both with WithPrincipal.
+const PrincipalKey contextKey = "auth.principal"
+
+// Principal is the authenticated caller, as asserted by the token claims.
+type Principal struct {
+	UserID uint
+	Email  string
+	Role   string
+	// System names an operator tool, such as the command line, acting
+	// outside any request. System principals have no UserID.
+	System string
+}
+
+// SystemPrincipal returns the principal of the operator tool name.
+func SystemPrincipal(name string) *Principal {
+	return &Principal{System: name}
+}
+
+func NewPrincipal(claims *Claims) *Principal {
+	return &Principal{
+		UserID: claims.UserID,
+		Email:  claims.Email,
+		Role:   claims.Role,
+	}
+}
+
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, PrincipalKey, principal)
+}
+
+// PrincipalFromContext returns the principal stored in ctx, if any.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(PrincipalKey).(*Principal)
+	return principal, ok && principal != nil
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// recoveryCodeAlphabet has 32 symbols so a random byte maps onto it without
+// bias; look-alike characters (i, l, o, 0) are left out.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz123456789"
+
+// GenerateRecoveryCodes returns n random codes formatted as xxxxx-xxxxx.
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		for j, b := range buf {
+			buf[j] = recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)]
+		}
+		codes[i] = string(buf[:5]) + "-" + string(buf[5:])
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode normalises a user-supplied code and returns the hex SHA-256
+// digest stored in the database. The codes carry enough entropy that a fast
+// hash is sufficient.
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.TrimSpace(code))
+	normalized = strings.NewReplacer("-", "", " ", "").Replace(normalized)
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
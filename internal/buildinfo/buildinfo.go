@@ -0,0 +1,64 @@
+// Package buildinfo describes the running binary. Version, Commit and
+// BuildTime are set at build time with -ldflags, for example:
+//
+//	go build -ldflags "-X github.com/faizalnurrozi/go-starter-kit/internal/buildinfo.Version=v1.2.0" ./cmd/server
+//
+// Unset values fall back to the VCS stamp Go embeds in the binary.
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Service names the application in health reports and metrics.
+const Service = "go-starter-kit"
+
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Service   string `json:"service"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the metadata of the running binary.
+func Get() Info {
+	info := Info{
+		Service:   Service,
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildTime == "":
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
+
+func (i Info) String() string {
+	return fmt.Sprintf("%s %s (commit %s, built %s, %s)", i.Service, i.Version, i.Commit, i.BuildTime, i.GoVersion)
+}
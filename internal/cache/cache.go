@@ -0,0 +1,130 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var log = logger.Named("cache")
+
+// ErrMiss is returned by Get when the key is not cached.
+var ErrMiss = errors.New("cache: miss")
+
+// Cache is a byte-oriented key/value cache with per-entry TTLs. A zero TTL
+// means the entry does not expire (but may still be evicted). Use Typed to
+// store values of a concrete type.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// MultiGet returns the cached values for keys; missing keys are absent
+	// from the result rather than reported as errors.
+	MultiGet(ctx context.Context, keys ...string) (map[string][]byte, error)
+	Close() error
+}
+
+// New returns the Cache selected by cfg.Cache.Driver. Keys are prefixed
+// with the configured namespace and key version, so bumping the version
+// stops reads of entries written in an older shape.
+func New(cfg *config.Config) (Cache, error) {
+	var c Cache
+	switch cfg.Cache.Driver {
+	case "redis":
+		client, err := newCheckedRedisClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		c = NewRedisCache(client)
+	case "tiered":
+		client, err := newCheckedRedisClient(cfg)
+		if err != nil {
+			return nil, err
+		}
+		c = NewTiered(client, TieredOptions{
+			L1Size:  cfg.Cache.MemorySize,
+			L1TTL:   time.Duration(cfg.Cache.NearTTL) * time.Second,
+			Channel: cfg.Cache.InvalidationChannel,
+		})
+	case "memory":
+		c = NewLRU(cfg.Cache.MemorySize)
+	case "none", "":
+		return NewNoop(), nil
+	default:
+		return nil, fmt.Errorf("unsupported cache driver: %s", cfg.Cache.Driver)
+	}
+
+	return WithPrefix(c, fmt.Sprintf("%s:v%d:", cfg.Cache.Namespace, cfg.Cache.KeyVersion)), nil
+}
+
+// newCheckedRedisClient pings Redis once at startup. An unreachable server
+// is an error unless redis.fail_open is set, in which case the client keeps
+// reconnecting in the background and cache calls fail until it succeeds.
+func newCheckedRedisClient(cfg *config.Config) (redis.UniversalClient, error) {
+	client, err := NewRedisClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		if !cfg.Redis.FailOpen {
+			client.Close()
+			return nil, fmt.Errorf("redis unreachable: %w", err)
+		}
+		log.Warn(context.Background(), "Redis unreachable, starting without it", logger.Err(err))
+	}
+	return client, nil
+}
+
+// Ping checks the server behind c. ok is false if c has none.
+func Ping(ctx context.Context, c Cache) (ok bool, err error) {
+	pinger, ok := PingerOf(c)
+	if !ok {
+		return false, nil
+	}
+	return true, pinger.Ping(ctx)
+}
+
+// PingerOf returns the Pinger behind c, if it talks to a server.
+func PingerOf(c Cache) (Pinger, bool) {
+	pinger, ok := unwrap(c).(Pinger)
+	return pinger, ok
+}
+
+// RedisClientOf returns the Redis client behind c, so other features such
+// as rate limiting can share its connection pool.
+func RedisClientOf(c Cache) (redis.UniversalClient, bool) {
+	provider, ok := unwrap(c).(interface{ RedisClient() redis.UniversalClient })
+	if !ok {
+		return nil, false
+	}
+	return provider.RedisClient(), true
+}
+
+// StatsOf returns the hit counters of c, if it keeps any.
+func StatsOf(c Cache) (Stats, bool) {
+	provider, ok := unwrap(c).(StatsProvider)
+	if !ok {
+		return Stats{}, false
+	}
+	return provider.Stats(), true
+}
+
+func unwrap(c Cache) Cache {
+	for {
+		wrapper, ok := c.(interface{ Unwrap() Cache })
+		if !ok {
+			return c
+		}
+		c = wrapper.Unwrap()
+	}
+}
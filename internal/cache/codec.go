@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Codec converts values of type T to and from their cached representation.
+type Codec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+type jsonCodec[T any] struct{}
+
+// JSONCodec encodes values with encoding/json.
+func JSONCodec[T any]() Codec[T] {
+	return jsonCodec[T]{}
+}
+
+func (jsonCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (jsonCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// Typed wraps a Cache with a Codec so callers work with T instead of bytes.
+type Typed[T any] struct {
+	cache Cache
+	codec Codec[T]
+}
+
+func NewTyped[T any](cache Cache, codec Codec[T]) *Typed[T] {
+	return &Typed[T]{cache: cache, codec: codec}
+}
+
+// Get returns the cached value and true, or the zero value and false on a
+// miss. Entries that fail to decode are treated as misses.
+func (t *Typed[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	var zero T
+
+	data, err := t.cache.Get(ctx, key)
+	if errors.Is(err, ErrMiss) {
+		return zero, false, nil
+	}
+	if err != nil {
+		return zero, false, err
+	}
+
+	value, err := t.codec.Unmarshal(data)
+	if err != nil {
+		return zero, false, nil
+	}
+	return value, true, nil
+}
+
+func (t *Typed[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	data, err := t.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return t.cache.Set(ctx, key, data, ttl)
+}
+
+func (t *Typed[T]) Delete(ctx context.Context, keys ...string) error {
+	return t.cache.Delete(ctx, keys...)
+}
+
+// MultiGet returns the decodable cached values for keys.
+func (t *Typed[T]) MultiGet(ctx context.Context, keys ...string) (map[string]T, error) {
+	raw, err := t.cache.MultiGet(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]T, len(raw))
+	for key, data := range raw {
+		value, err := t.codec.Unmarshal(data)
+		if err != nil {
+			continue
+		}
+		values[key] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// Group invalidates an open-ended set of keys at once. Keys are prefixed
+// with the group's current generation; Invalidate starts a new generation,
+// so older entries are never read again and expire by their TTL.
+type Group struct {
+	cache Cache
+	name  string
+}
+
+func NewGroup(c Cache, name string) *Group {
+	return &Group{cache: c, name: name}
+}
+
+// Key returns key scoped to the current generation.
+func (g *Group) Key(ctx context.Context, key string) (string, error) {
+	generation, err := g.cache.Get(ctx, g.generationKey())
+	if errors.Is(err, ErrMiss) {
+		// Also covers an evicted generation, which must not fall back to a
+		// value that was in use before
+		generation, err = g.next(ctx)
+	}
+	if err != nil {
+		return "", err
+	}
+	return g.name + ":" + string(generation) + ":" + key, nil
+}
+
+func (g *Group) Invalidate(ctx context.Context) error {
+	_, err := g.next(ctx)
+	return err
+}
+
+func (g *Group) next(ctx context.Context) ([]byte, error) {
+	generation := []byte(strconv.FormatInt(time.Now().UnixNano(), 36))
+	return generation, g.cache.Set(ctx, g.generationKey(), generation, 0)
+}
+
+func (g *Group) generationKey() string {
+	return g.name + ":generation"
+}
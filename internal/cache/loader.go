@@ -0,0 +1,201 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound is returned by a load function when the value does not exist.
+// The Loader caches that result for NegativeTTL and returns ErrNotFound to
+// later callers without calling load again.
+var ErrNotFound = errors.New("cache: not found")
+
+type LoaderOptions struct {
+	// TTL is how long a loaded value is considered fresh.
+	TTL time.Duration
+	// NegativeTTL is how long a not-found result is cached. Zero disables
+	// negative caching.
+	NegativeTTL time.Duration
+	// Jitter spreads expiries by up to this fraction of the TTL so entries
+	// written together do not expire together.
+	Jitter float64
+	// StaleTTL keeps values this long past TTL. A stale value is returned
+	// immediately while it is reloaded in the background. Zero disables
+	// stale-while-revalidate.
+	StaleTTL time.Duration
+	// DeleteDelay schedules a second Delete this long after the first, to
+	// drop values re-cached by reads that raced with the write. Zero
+	// disables the second delete.
+	DeleteDelay time.Duration
+	// OnLookup, if set, is called with the result of every Get.
+	OnLookup func(result string)
+}
+
+// Lookup results reported to LoaderOptions.OnLookup.
+const (
+	LookupHit         = "hit"
+	LookupStale       = "stale"
+	LookupNegativeHit = "negative_hit"
+	LookupMiss        = "miss"
+)
+
+// entry is what a Loader stores under a key.
+type entry struct {
+	Value      []byte    `json:"v,omitempty"`
+	Missing    bool      `json:"m,omitempty"`
+	FreshUntil time.Time `json:"f"`
+}
+
+// Loader is a read-through cache. Concurrent misses for the same key share a
+// single call to load.
+type Loader[T any] struct {
+	cache Cache
+	codec Codec[T]
+	opts  LoaderOptions
+	group singleflight.Group
+}
+
+func NewLoader[T any](cache Cache, codec Codec[T], opts LoaderOptions) *Loader[T] {
+	return &Loader[T]{cache: cache, codec: codec, opts: opts}
+}
+
+// Get returns the cached value for key, calling load on a miss. Cache errors
+// are logged and fall back to load.
+func (l *Loader[T]) Get(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+
+	if e, ok := l.read(ctx, key); ok {
+		result := LookupHit
+		if time.Now().After(e.FreshUntil) {
+			l.refresh(ctx, key, load)
+			result = LookupStale
+		}
+		if e.Missing {
+			l.observe(LookupNegativeHit)
+			return zero, ErrNotFound
+		}
+		if value, err := l.codec.Unmarshal(e.Value); err == nil {
+			l.observe(result)
+			return value, nil
+		}
+	}
+	l.observe(LookupMiss)
+
+	// The load outlives any single caller that shares it
+	result, err, _ := l.group.Do(key, func() (interface{}, error) {
+		return l.load(context.WithoutCancel(ctx), key, load)
+	})
+	if err != nil {
+		return zero, err
+	}
+	return result.(T), nil
+}
+
+// Set stores value under key as if it had just been loaded.
+func (l *Loader[T]) Set(ctx context.Context, key string, value T) error {
+	data, err := l.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return l.write(ctx, key, entry{Value: data}, l.opts.TTL)
+}
+
+// Delete removes keys after the underlying data has been written. Loads
+// already in flight are detached so later callers start a fresh one, and a
+// second delete follows after DeleteDelay in case one of those loads read the
+// old row before the write and caches it after this call.
+func (l *Loader[T]) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		l.group.Forget(key)
+	}
+
+	if l.opts.DeleteDelay > 0 {
+		time.AfterFunc(l.opts.DeleteDelay, func() {
+			if err := l.cache.Delete(context.Background(), keys...); err != nil {
+				log.Warn(context.Background(), "Error deleting cache entries", logger.Err(err))
+			}
+		})
+	}
+
+	return l.cache.Delete(ctx, keys...)
+}
+
+func (l *Loader[T]) load(ctx context.Context, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	value, err := load(ctx)
+	if errors.Is(err, ErrNotFound) {
+		if l.opts.NegativeTTL > 0 {
+			if err := l.write(ctx, key, entry{Missing: true}, l.opts.NegativeTTL); err != nil {
+				log.Warn(ctx, "Error caching missing entry", logger.Err(err))
+			}
+		}
+		return value, ErrNotFound
+	}
+	if err != nil {
+		return value, err
+	}
+
+	if err := l.Set(ctx, key, value); err != nil {
+		log.Warn(ctx, "Error caching entry", logger.Err(err))
+	}
+	return value, nil
+}
+
+// refresh reloads a stale key in the background. It shares the singleflight
+// group with Get, so at most one reload per key runs at a time.
+func (l *Loader[T]) refresh(ctx context.Context, key string, load func(ctx context.Context) (T, error)) {
+	ctx = context.WithoutCancel(ctx)
+	go l.group.Do(key, func() (interface{}, error) {
+		return l.load(ctx, key, load)
+	})
+}
+
+func (l *Loader[T]) read(ctx context.Context, key string) (entry, bool) {
+	var e entry
+
+	data, err := l.cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, ErrMiss) {
+			log.Warn(ctx, "Error reading cache entry", logger.Err(err))
+		}
+		return e, false
+	}
+
+	if err := json.Unmarshal(data, &e); err != nil {
+		return e, false
+	}
+	return e, true
+}
+
+// write stores e for a jittered ttl, plus StaleTTL so the entry can still be
+// served while it is refreshed.
+func (l *Loader[T]) write(ctx context.Context, key string, e entry, ttl time.Duration) error {
+	ttl = l.jitter(ttl)
+	e.FreshUntil = time.Now().Add(ttl)
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return l.cache.Set(ctx, key, data, ttl+l.opts.StaleTTL)
+}
+
+func (l *Loader[T]) observe(result string) {
+	if l.opts.OnLookup != nil {
+		l.opts.OnLookup(result)
+	}
+}
+
+func (l *Loader[T]) jitter(ttl time.Duration) time.Duration {
+	if l.opts.Jitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+	spread := float64(ttl) * l.opts.Jitter
+	return ttl + time.Duration((rand.Float64()*2-1)*spread)
+}
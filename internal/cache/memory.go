@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+const defaultLRUSize = 10000
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// lruCache is an in-process cache holding at most size entries, evicting the
+// least recently used one when full. Expired entries are dropped lazily.
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	items map[string]*list.Element
+	order *list.List
+	now   func() time.Time
+}
+
+// NewLRU returns an in-process Cache bounded to size entries. It is not
+// shared between processes, so it suits single-instance deployments, tests
+// and as a near cache in front of Redis.
+func NewLRU(size int) Cache {
+	if size <= 0 {
+		size = defaultLRUSize
+	}
+	return &lruCache{
+		size:  size,
+		items: make(map[string]*list.Element, size),
+		order: list.New(),
+		now:   time.Now,
+	}
+}
+
+func (c *lruCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.get(key)
+	if !ok {
+		return nil, ErrMiss
+	}
+	return value, nil
+}
+
+func (c *lruCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	// Copy so later changes to the caller's slice don't leak into the cache
+	stored := append([]byte(nil), value...)
+
+	if elem, ok := c.items[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		entry.value = stored
+		entry.expiresAt = expiresAt
+		c.order.MoveToFront(elem)
+		return nil
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: stored, expiresAt: expiresAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+func (c *lruCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.remove(elem)
+		}
+	}
+	return nil
+}
+
+func (c *lruCache) MultiGet(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if value, ok := c.get(key); ok {
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+func (c *lruCache) Close() error {
+	return nil
+}
+
+// get must be called with mu held.
+func (c *lruCache) get(key string) ([]byte, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*lruEntry)
+	if !entry.expiresAt.IsZero() && c.now().After(entry.expiresAt) {
+		c.remove(elem)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return append([]byte(nil), entry.value...), true
+}
+
+// remove must be called with mu held.
+func (c *lruCache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*lruEntry).key)
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+type noopCache struct{}
+
+// NewNoop returns a Cache that stores nothing, so every Get misses. It is
+// used when caching is disabled.
+func NewNoop() Cache {
+	return noopCache{}
+}
+
+func (noopCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return nil, ErrMiss
+}
+
+func (noopCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return nil
+}
+
+func (noopCache) Delete(ctx context.Context, keys ...string) error {
+	return nil
+}
+
+func (noopCache) MultiGet(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	return map[string][]byte{}, nil
+}
+
+func (noopCache) Close() error {
+	return nil
+}
@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+type prefixCache struct {
+	cache  Cache
+	prefix string
+}
+
+// WithPrefix namespaces every key written through c. Changing the prefix
+// orphans existing entries, which then expire on their own.
+func WithPrefix(c Cache, prefix string) Cache {
+	return &prefixCache{cache: c, prefix: prefix}
+}
+
+func (p *prefixCache) Get(ctx context.Context, key string) ([]byte, error) {
+	return p.cache.Get(ctx, p.prefix+key)
+}
+
+func (p *prefixCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return p.cache.Set(ctx, p.prefix+key, value, ttl)
+}
+
+func (p *prefixCache) Delete(ctx context.Context, keys ...string) error {
+	return p.cache.Delete(ctx, p.keys(keys)...)
+}
+
+func (p *prefixCache) MultiGet(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	raw, err := p.cache.MultiGet(ctx, p.keys(keys)...)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string][]byte, len(raw))
+	for key, value := range raw {
+		values[strings.TrimPrefix(key, p.prefix)] = value
+	}
+	return values, nil
+}
+
+func (p *prefixCache) Unwrap() Cache {
+	return p.cache
+}
+
+func (p *prefixCache) Close() error {
+	return p.cache.Close()
+}
+
+func (p *prefixCache) keys(keys []string) []string {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = p.prefix + key
+	}
+	return prefixed
+}
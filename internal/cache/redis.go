@@ -1,17 +1,194 @@
 package cache
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
 	"github.com/faizalnurrozi/go-starter-kit/internal/config"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
 )
 
-func NewRedisClient(cfg *config.Config) *redis.Client {
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
-		Password: cfg.Redis.Password,
-		DB:       cfg.Redis.DB,
+// Pinger is implemented by caches backed by a remote server.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// NewRedisClient returns a standalone, Sentinel or Cluster client as selected
+// by cfg.Redis.Mode.
+func NewRedisClient(cfg *config.Config) (redis.UniversalClient, error) {
+	opts := &redis.UniversalOptions{
+		Addrs:            cfg.Redis.Addrs,
+		Username:         cfg.Redis.Username,
+		Password:         cfg.Redis.Password.Value(),
+		SentinelPassword: cfg.Redis.SentinelPassword.Value(),
+		DB:               cfg.Redis.DB,
+		PoolSize:         cfg.Redis.PoolSize,
+		MinIdleConns:     cfg.Redis.MinIdleConns,
+		DialTimeout:      time.Duration(cfg.Redis.DialTimeout) * time.Millisecond,
+		ReadTimeout:      time.Duration(cfg.Redis.ReadTimeout) * time.Millisecond,
+		WriteTimeout:     time.Duration(cfg.Redis.WriteTimeout) * time.Millisecond,
+	}
+	if len(opts.Addrs) == 0 {
+		opts.Addrs = []string{cfg.Redis.Host + ":" + cfg.Redis.Port}
+	}
+
+	switch cfg.Redis.Mode {
+	case "standalone", "":
+		if len(opts.Addrs) > 1 {
+			return nil, errors.New("redis standalone mode takes a single address")
+		}
+	case "sentinel":
+		if cfg.Redis.MasterName == "" {
+			return nil, errors.New("redis sentinel mode requires master_name")
+		}
+		opts.MasterName = cfg.Redis.MasterName
+	case "cluster":
+		opts.IsClusterMode = true
+	default:
+		return nil, fmt.Errorf("unsupported redis mode: %s", cfg.Redis.Mode)
+	}
+
+	if cfg.Redis.TLS.Enabled {
+		tlsConfig, err := newTLSConfig(cfg.Redis.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts.TLSConfig = tlsConfig
+	}
+
+	// Spans go to the global tracer provider, a no-op unless tracing is on
+	client := redis.NewUniversalClient(opts)
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+func newTLSConfig(cfg config.RedisTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read redis CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load redis client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+type redisCache struct {
+	client redis.UniversalClient
+}
+
+// NewRedisCache returns a Cache backed by client. Closing the cache closes
+// the client.
+func NewRedisCache(client redis.UniversalClient) Cache {
+	return &redisCache{client: client}
+}
+
+func (c *redisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrMiss
+	}
+	return data, err
+}
+
+func (c *redisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete and MultiGet pipeline one command per key on a cluster, where
+// multi-key commands fail unless every key hashes to the same slot.
+func (c *redisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if _, ok := c.client.(*redis.ClusterClient); !ok {
+		return c.client.Del(ctx, keys...).Err()
+	}
+
+	_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Del(ctx, key)
+		}
+		return nil
 	})
+	return err
+}
+
+func (c *redisCache) MultiGet(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	if _, ok := c.client.(*redis.ClusterClient); ok {
+		cmds := make([]*redis.StringCmd, len(keys))
+		_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, key := range keys {
+				cmds[i] = pipe.Get(ctx, key)
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return nil, err
+		}
+		for i, cmd := range cmds {
+			if data, err := cmd.Bytes(); err == nil {
+				values[keys[i]] = data
+			}
+		}
+		return values, nil
+	}
+
+	results, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		if s, ok := result.(string); ok {
+			values[keys[i]] = []byte(s)
+		}
+	}
+	return values, nil
+}
+
+func (c *redisCache) RedisClient() redis.UniversalClient {
+	return c.client
+}
+
+func (c *redisCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
 
-	return rdb
+func (c *redisCache) Close() error {
+	return c.client.Close()
 }
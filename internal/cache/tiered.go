@@ -0,0 +1,220 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Stats are cumulative lookup counters for a tiered cache.
+type Stats struct {
+	L1Hits uint64 `json:"l1_hits"`
+	L2Hits uint64 `json:"l2_hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// L1HitRate is the share of lookups served from process memory.
+func (s Stats) L1HitRate() float64 {
+	return ratio(s.L1Hits, s.L1Hits+s.L2Hits+s.Misses)
+}
+
+// L2HitRate is the share of L1 misses served from Redis.
+func (s Stats) L2HitRate() float64 {
+	return ratio(s.L2Hits, s.L2Hits+s.Misses)
+}
+
+// StatsProvider is implemented by caches that track hit rates.
+type StatsProvider interface {
+	Stats() Stats
+}
+
+type TieredOptions struct {
+	// L1Size bounds the number of entries kept in process memory.
+	L1Size int
+	// L1TTL caps how long an entry lives in process memory. It also bounds
+	// staleness if an invalidation message is lost.
+	L1TTL time.Duration
+	// Channel is the Redis pub/sub channel used to broadcast invalidations.
+	Channel string
+}
+
+type invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys"`
+}
+
+// tieredCache keeps a small, short-lived in-process LRU (L1) in front of
+// Redis (L2). Every Set and Delete is published on a Redis channel so other
+// instances drop the key from their L1.
+type tieredCache struct {
+	l1      Cache
+	l2      Cache
+	client  redis.UniversalClient
+	pubsub  *redis.PubSub
+	origin  string
+	l1TTL   time.Duration
+	channel string
+
+	l1Hits atomic.Uint64
+	l2Hits atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NewTiered returns a two-tier cache over client. Closing it closes client.
+func NewTiered(client redis.UniversalClient, opts TieredOptions) Cache {
+	c := &tieredCache{
+		l1:      NewLRU(opts.L1Size),
+		l2:      NewRedisCache(client),
+		client:  client,
+		origin:  newOrigin(),
+		l1TTL:   opts.L1TTL,
+		channel: opts.Channel,
+	}
+
+	c.pubsub = client.Subscribe(context.Background(), opts.Channel)
+	go c.listen()
+
+	return c
+}
+
+func (c *tieredCache) Get(ctx context.Context, key string) ([]byte, error) {
+	if value, err := c.l1.Get(ctx, key); err == nil {
+		c.l1Hits.Add(1)
+		return value, nil
+	}
+
+	value, err := c.l2.Get(ctx, key)
+	if errors.Is(err, ErrMiss) {
+		c.misses.Add(1)
+		return nil, ErrMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	c.l2Hits.Add(1)
+	c.l1.Set(ctx, key, value, c.l1TTL)
+	return value, nil
+}
+
+func (c *tieredCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.l2.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	l1TTL := c.l1TTL
+	if ttl > 0 && ttl < l1TTL {
+		l1TTL = ttl
+	}
+	c.l1.Set(ctx, key, value, l1TTL)
+
+	return c.publish(ctx, key)
+}
+
+func (c *tieredCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	c.l1.Delete(ctx, keys...)
+	if err := c.l2.Delete(ctx, keys...); err != nil {
+		return err
+	}
+
+	return c.publish(ctx, keys...)
+}
+
+func (c *tieredCache) MultiGet(ctx context.Context, keys ...string) (map[string][]byte, error) {
+	values, _ := c.l1.MultiGet(ctx, keys...)
+	c.l1Hits.Add(uint64(len(values)))
+
+	var missing []string
+	for _, key := range keys {
+		if _, ok := values[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	fetched, err := c.l2.MultiGet(ctx, missing...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.l2Hits.Add(uint64(len(fetched)))
+	c.misses.Add(uint64(len(missing) - len(fetched)))
+	for key, value := range fetched {
+		values[key] = value
+		c.l1.Set(ctx, key, value, c.l1TTL)
+	}
+	return values, nil
+}
+
+func (c *tieredCache) Stats() Stats {
+	return Stats{
+		L1Hits: c.l1Hits.Load(),
+		L2Hits: c.l2Hits.Load(),
+		Misses: c.misses.Load(),
+	}
+}
+
+func (c *tieredCache) RedisClient() redis.UniversalClient {
+	return c.client
+}
+
+func (c *tieredCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+func (c *tieredCache) Close() error {
+	c.pubsub.Close()
+	return c.client.Close()
+}
+
+func (c *tieredCache) publish(ctx context.Context, keys ...string) error {
+	payload, err := json.Marshal(invalidation{Origin: c.origin, Keys: keys})
+	if err != nil {
+		return err
+	}
+	return c.client.Publish(ctx, c.channel, payload).Err()
+}
+
+// listen applies invalidations published by other instances until the
+// subscription is closed. go-redis resubscribes after reconnecting; messages
+// sent while disconnected are lost, which L1TTL bounds.
+func (c *tieredCache) listen() {
+	for msg := range c.pubsub.Channel() {
+		var inv invalidation
+		if err := json.Unmarshal([]byte(msg.Payload), &inv); err != nil {
+			log.Warn(context.Background(), "Invalid cache invalidation message", logger.Err(err))
+			continue
+		}
+		if inv.Origin == c.origin {
+			continue
+		}
+		c.l1.Delete(context.Background(), inv.Keys...)
+	}
+}
+
+func newOrigin() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+func ratio(part, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) / float64(total)
+}
@@ -1,105 +1,410 @@
 package config
 
 import (
+    "errors"
+    "fmt"
     "log"
+    "os"
     
     "github.com/joho/godotenv"
     "github.com/spf13/viper"
 )
 
 type Config struct {
-    Server   ServerConfig   `mapstructure:"server"`
-    Database DatabaseConfig `mapstructure:"database"`
-    Redis    RedisConfig    `mapstructure:"redis"`
-    GRPC     GRPCConfig     `mapstructure:"grpc"`
-    JWT      JWTConfig      `mapstructure:"jwt"`
-    Log      LogConfig      `mapstructure:"log"`
+    Server       ServerConfig       `mapstructure:"server"`
+    Database     DatabaseConfig     `mapstructure:"database"`
+    Redis        RedisConfig        `mapstructure:"redis"`
+    Cache        CacheConfig        `mapstructure:"cache"`
+    HTTPCache    HTTPCacheConfig    `mapstructure:"http_cache"`
+    GRPC         GRPCConfig         `mapstructure:"grpc"`
+    Admin        AdminConfig        `mapstructure:"admin"`
+    Health       HealthConfig       `mapstructure:"health"`
+    RateLimit    RateLimitConfig    `mapstructure:"rate_limit"`
+    CORS         CORSConfig         `mapstructure:"cors"`
+    Tracing      TracingConfig      `mapstructure:"tracing"`
+    JWT          JWTConfig          `mapstructure:"jwt"`
+    MFA          MFAConfig          `mapstructure:"mfa"`
+    Mail         MailConfig         `mapstructure:"mail"`
+    Verification VerificationConfig `mapstructure:"verification"`
+    Log          LogConfig          `mapstructure:"log"`
 }
 
 type ServerConfig struct {
-    Port string `mapstructure:"port"`
-    Host string `mapstructure:"host"`
+    Port            string   `mapstructure:"port"`
+    Host            string   `mapstructure:"host"`
+    ShutdownTimeout int      `mapstructure:"shutdown_timeout"`
+    // ShutdownDelay is how long readiness fails before the servers stop
+    ShutdownDelay   int      `mapstructure:"shutdown_delay"`
+    // ProxyHeader carries the client address set by a reverse proxy. It is
+    // only read from TrustedProxies, given as IPs or CIDR ranges.
+    ProxyHeader     string   `mapstructure:"proxy_header"`
+    TrustedProxies  []string `mapstructure:"trusted_proxies"`
 }
 
 type DatabaseConfig struct {
-    Driver   string `mapstructure:"driver"`
-    Host     string `mapstructure:"host"`
-    Port     string `mapstructure:"port"`
-    Username string `mapstructure:"username"`
-    Password string `mapstructure:"password"`
-    Database string `mapstructure:"database"`
-    SSLMode  string `mapstructure:"ssl_mode"`
+    Driver      string `mapstructure:"driver"`
+    Host        string `mapstructure:"host"`
+    Port        string `mapstructure:"port"`
+    Username    string `mapstructure:"username"`
+    Password    Secret `mapstructure:"password"`
+    Database    string `mapstructure:"database"`
+    SSLMode     string `mapstructure:"ssl_mode"`
+    AutoMigrate bool   `mapstructure:"auto_migrate"`
 }
 
 type RedisConfig struct {
-    Host     string `mapstructure:"host"`
-    Port     string `mapstructure:"port"`
-    Password string `mapstructure:"password"`
-    DB       int    `mapstructure:"db"`
+    Mode             string         `mapstructure:"mode"`
+    Host             string         `mapstructure:"host"`
+    Port             string         `mapstructure:"port"`
+    Addrs            []string       `mapstructure:"addrs"`
+    MasterName       string         `mapstructure:"master_name"`
+    Username         string         `mapstructure:"username"`
+    Password         Secret         `mapstructure:"password"`
+    SentinelPassword Secret         `mapstructure:"sentinel_password"`
+    DB               int            `mapstructure:"db"`
+    PoolSize         int            `mapstructure:"pool_size"`
+    MinIdleConns     int            `mapstructure:"min_idle_conns"`
+    DialTimeout      int            `mapstructure:"dial_timeout"`
+    ReadTimeout      int            `mapstructure:"read_timeout"`
+    WriteTimeout     int            `mapstructure:"write_timeout"`
+    FailOpen         bool           `mapstructure:"fail_open"`
+    TLS              RedisTLSConfig `mapstructure:"tls"`
+}
+
+type RedisTLSConfig struct {
+    Enabled            bool   `mapstructure:"enabled"`
+    CAFile             string `mapstructure:"ca_file"`
+    CertFile           string `mapstructure:"cert_file"`
+    KeyFile            string `mapstructure:"key_file"`
+    ServerName         string `mapstructure:"server_name"`
+    InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+}
+
+type CacheConfig struct {
+    Driver              string  `mapstructure:"driver"`
+    MemorySize          int     `mapstructure:"memory_size"`
+    NearTTL             int     `mapstructure:"near_ttl"`
+    InvalidationChannel string  `mapstructure:"invalidation_channel"`
+    TTL                 int     `mapstructure:"ttl"`
+    NegativeTTL         int     `mapstructure:"negative_ttl"`
+    TTLJitter           float64 `mapstructure:"ttl_jitter"`
+    StaleTTL            int     `mapstructure:"stale_ttl"`
+    DeleteDelay         int     `mapstructure:"delete_delay"`
+    Namespace           string  `mapstructure:"namespace"`
+    KeyVersion          int     `mapstructure:"key_version"`
+}
+
+type HTTPCacheConfig struct {
+    UserCacheControl     string `mapstructure:"user_cache_control"`
+    UserListCacheControl string `mapstructure:"user_list_cache_control"`
+    ListCache            bool   `mapstructure:"list_cache"`
+    ListCacheTTL         int    `mapstructure:"list_cache_ttl"`
 }
 
 type GRPCConfig struct {
     Port string `mapstructure:"port"`
 }
 
+type AdminConfig struct {
+    Enabled     bool   `mapstructure:"enabled"`
+    // Host is the interface to listen on; the admin endpoints are not
+    // authenticated, so it defaults to loopback
+    Host        string `mapstructure:"host"`
+    Port        string `mapstructure:"port"`
+    MetricsPath string `mapstructure:"metrics_path"`
+}
+
+type HealthConfig struct {
+    Timeout  int `mapstructure:"timeout"`
+    CacheTTL int `mapstructure:"cache_ttl"`
+}
+
+// RateLimitConfig limits requests per client. Rules are "<requests>/<window>",
+// e.g. "100/1m", or "off". Routes and methods are lists of "<pattern> <rule>"
+// (lists, since viper lowercases and splits map keys): route patterns are
+// "METHOD /path" or "/path" where a trailing * matches any suffix, and
+// method patterns are full gRPC method names.
+type RateLimitConfig struct {
+    Enabled      bool              `mapstructure:"enabled" reload:"safe"`
+    Driver       string            `mapstructure:"driver"`
+    FailOpen     bool              `mapstructure:"fail_open" reload:"safe"`
+    KeyBy        []string          `mapstructure:"key_by" reload:"safe"`
+    APIKeyHeader string            `mapstructure:"api_key_header" reload:"safe"`
+    Default      string            `mapstructure:"default" reload:"safe"`
+    Routes       []string          `mapstructure:"routes" reload:"safe"`
+    Methods      []string          `mapstructure:"methods" reload:"safe"`
+}
+
+// CORSConfig is the cross-origin policy of the HTTP API. Origins are exact,
+// such as "https://app.example.com", match any subdomain, such as
+// "https://*.example.com", or are "*" for any origin.
+type CORSConfig struct {
+    AllowOrigins     []string `mapstructure:"allow_origins" reload:"safe"`
+    AllowMethods     []string `mapstructure:"allow_methods"`
+    AllowHeaders     []string `mapstructure:"allow_headers"`
+    ExposeHeaders    []string `mapstructure:"expose_headers"`
+    AllowCredentials bool     `mapstructure:"allow_credentials"`
+    MaxAge           int      `mapstructure:"max_age"`
+}
+
+type TracingConfig struct {
+    Exporter    string  `mapstructure:"exporter"`
+    ServiceName string  `mapstructure:"service_name"`
+    Endpoint    string  `mapstructure:"endpoint"`
+    Insecure    bool    `mapstructure:"insecure"`
+    FilePath    string  `mapstructure:"file_path"`
+    SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
 type JWTConfig struct {
-    Secret string `mapstructure:"secret"`
+    Secret Secret `mapstructure:"secret"`
     Expire int    `mapstructure:"expire"`
 }
 
+type MFAConfig struct {
+    Issuer             string   `mapstructure:"issuer"`
+    RequiredRoles      []string `mapstructure:"required_roles"`
+    PendingTokenExpire int      `mapstructure:"pending_token_expire"`
+    RecoveryCodeCount  int      `mapstructure:"recovery_code_count"`
+    // MaxFailedAttempts wrong codes in a row lock sign-in verification
+    // for Lockout minutes
+    MaxFailedAttempts  int      `mapstructure:"max_failed_attempts"`
+    Lockout            int      `mapstructure:"lockout"`
+}
+
+type MailConfig struct {
+    Driver   string `mapstructure:"driver"`
+    From     string `mapstructure:"from"`
+    Host     string `mapstructure:"host"`
+    Port     string `mapstructure:"port"`
+    Username string `mapstructure:"username"`
+    Password Secret `mapstructure:"password"`
+    FileDir  string `mapstructure:"file_dir"`
+}
+
+type VerificationConfig struct {
+    RequireVerifiedEmail bool   `mapstructure:"require_verified_email"`
+    TokenExpire          int    `mapstructure:"token_expire"`
+    URL                  string `mapstructure:"url"`
+}
+
 type LogConfig struct {
-    Level string `mapstructure:"level"`
+    Level      string            `mapstructure:"level" reload:"safe"`
+    Format     string            `mapstructure:"format"`
+    Levels     map[string]string `mapstructure:"levels" reload:"safe"`
+    RedactKeys []string          `mapstructure:"redact_keys"`
+    Sampling   LogSamplingConfig `mapstructure:"sampling"`
 }
 
-var globalConfig *Config
+type LogSamplingConfig struct {
+    Enabled    bool `mapstructure:"enabled"`
+    Initial    int  `mapstructure:"initial"`
+    Thereafter int  `mapstructure:"thereafter"`
+    Interval   int  `mapstructure:"interval"`
+}
+
+// Option changes how the configuration is read.
+type Option func(*options)
+
+type options struct {
+    profile   string
+    overrides map[string]string
+}
+
+// WithProfile layers config.<profile>.yaml over config.yaml, instead of the
+// profile named by APP_ENV.
+func WithProfile(profile string) Option {
+    return func(o *options) {
+        o.profile = profile
+    }
+}
+
+// WithOverrides sets keys such as log.level, taking precedence over the
+// files and the environment. The --set flag uses it.
+func WithOverrides(values map[string]string) Option {
+    return func(o *options) {
+        o.overrides = values
+    }
+}
+
+func newOptions(opts []Option) options {
+    o := options{profile: os.Getenv("APP_ENV")}
+    for _, opt := range opts {
+        opt(&o)
+    }
+    return o
+}
+
+// Load reads and validates the configuration. A missing config file is
+// allowed, as long as defaults and environment variables make a valid
+// configuration; the error lists every invalid setting.
+func Load(opts ...Option) (*Config, error) {
+    config, err := Read(opts...)
+    if err != nil {
+        return nil, err
+    }
+    if err := Validate(config); err != nil {
+        return nil, fmt.Errorf("invalid configuration:\n%w", err)
+    }
+    return config, nil
+}
 
-func Load() *Config {
+// Default returns the built-in defaults without reading files or the
+// environment. It is not valid on its own: jwt.secret has no default.
+func Default() *Config {
+    v := viper.New()
+    setDefaults(v)
+    
+    var config Config
+    if err := v.Unmarshal(&config, decodeHook()); err != nil {
+        panic(fmt.Sprintf("config: invalid defaults: %v", err))
+    }
+    return &config
+}
+
+// Read loads the configuration without validating it. Each layer overrides
+// the one before: defaults, config.yaml, the profile's config.<env>.yaml,
+// environment variables, then overrides.
+func Read(opts ...Option) (*Config, error) {
+    config, _, err := read(newOptions(opts))
+    return config, err
+}
+
+// read returns the configuration and the files it was read from.
+func read(o options) (*Config, []string, error) {
     // Load .env file
     if err := godotenv.Load(); err != nil {
         log.Println("No .env file found")
     }
     
-    viper.SetConfigName("config")
-    viper.SetConfigType("yaml")
-    viper.AddConfigPath("./configs")
-    viper.AddConfigPath(".")
+    v := viper.New()
+    v.SetConfigType("yaml")
+    v.AddConfigPath("./configs")
+    v.AddConfigPath(".")
     
     // Set defaults
-    setDefaults()
+    setDefaults(v)
     
-    // Read environment variables
-    viper.AutomaticEnv()
+    var files []string
+    v.SetConfigName("config")
+    if err := v.ReadInConfig(); err != nil {
+        var notFound viper.ConfigFileNotFoundError
+        if !errors.As(err, &notFound) {
+            return nil, nil, fmt.Errorf("error reading config file: %w", err)
+        }
+        log.Println("No config file found, using defaults and environment")
+    } else {
+        files = append(files, v.ConfigFileUsed())
+    }
     
-    if err := viper.ReadInConfig(); err != nil {
-        log.Printf("Error reading config file: %v", err)
+    // A profile is asked for explicitly, so its file must exist
+    if o.profile != "" {
+        v.SetConfigName("config." + o.profile)
+        if err := v.MergeInConfig(); err != nil {
+            return nil, nil, fmt.Errorf("error reading config for profile %q: %w", o.profile, err)
+        }
+        files = append(files, v.ConfigFileUsed())
     }
     
-    var config Config
-    if err := viper.Unmarshal(&config); err != nil {
-        log.Fatal("Unable to decode into struct:", err)
+    // Environment variables override the files
+    if err := bindEnv(v); err != nil {
+        return nil, nil, err
     }
     
-    globalConfig = &config
-    return &config
+    if err := setOverrides(v, o.overrides); err != nil {
+        return nil, nil, err
+    }
+    
+    var config Config
+    if err := v.Unmarshal(&config, decodeHook()); err != nil {
+        return nil, nil, fmt.Errorf("unable to decode config: %w", err)
+    }
+    return &config, files, nil
 }
 
-func Get() *Config {
-    if globalConfig == nil {
-        return Load()
-    }
-    return globalConfig
-}
-
-func setDefaults() {
-    viper.SetDefault("server.port", "8080")
-    viper.SetDefault("server.host", "localhost")
-    viper.SetDefault("database.driver", "postgres")
-    viper.SetDefault("database.host", "localhost")
-    viper.SetDefault("database.port", "5432")
-    viper.SetDefault("redis.host", "localhost")
-    viper.SetDefault("redis.port", "6379")
-    viper.SetDefault("redis.db", 0)
-    viper.SetDefault("grpc.port", "9090")
-    viper.SetDefault("jwt.expire", 24)
-    viper.SetDefault("log.level", "info")
+func setDefaults(v *viper.Viper) {
+    v.SetDefault("server.port", "8080")
+    v.SetDefault("server.host", "localhost")
+    v.SetDefault("server.shutdown_timeout", 30)
+    v.SetDefault("server.shutdown_delay", 5)
+    v.SetDefault("server.proxy_header", "")
+    v.SetDefault("server.trusted_proxies", []string{})
+    v.SetDefault("database.driver", "postgres")
+    v.SetDefault("database.host", "localhost")
+    v.SetDefault("database.port", "5432")
+    v.SetDefault("database.auto_migrate", true)
+    v.SetDefault("redis.host", "localhost")
+    v.SetDefault("redis.port", "6379")
+    v.SetDefault("redis.db", 0)
+    v.SetDefault("redis.mode", "standalone")
+    v.SetDefault("redis.pool_size", 0)
+    v.SetDefault("redis.min_idle_conns", 0)
+    v.SetDefault("redis.dial_timeout", 5000)
+    v.SetDefault("redis.read_timeout", 3000)
+    v.SetDefault("redis.write_timeout", 3000)
+    v.SetDefault("redis.fail_open", false)
+    v.SetDefault("redis.tls.enabled", false)
+    v.SetDefault("cache.driver", "redis")
+    v.SetDefault("cache.memory_size", 10000)
+    v.SetDefault("cache.near_ttl", 30)
+    v.SetDefault("cache.invalidation_channel", "cache:invalidate")
+    v.SetDefault("cache.ttl", 900)
+    v.SetDefault("cache.negative_ttl", 30)
+    v.SetDefault("cache.ttl_jitter", 0.1)
+    v.SetDefault("cache.stale_ttl", 0)
+    v.SetDefault("cache.delete_delay", 500)
+    v.SetDefault("cache.namespace", "go-starter-kit")
+    v.SetDefault("cache.key_version", 1)
+    v.SetDefault("http_cache.user_cache_control", "private, no-cache")
+    v.SetDefault("http_cache.user_list_cache_control", "private, no-cache")
+    v.SetDefault("http_cache.list_cache", false)
+    v.SetDefault("http_cache.list_cache_ttl", 30)
+    v.SetDefault("grpc.port", "9090")
+    v.SetDefault("admin.enabled", true)
+    v.SetDefault("admin.host", "127.0.0.1")
+    v.SetDefault("admin.port", "8081")
+    v.SetDefault("admin.metrics_path", "/metrics")
+    v.SetDefault("health.timeout", 2000)
+    v.SetDefault("health.cache_ttl", 1000)
+    v.SetDefault("rate_limit.enabled", false)
+    v.SetDefault("rate_limit.driver", "redis")
+    v.SetDefault("rate_limit.fail_open", true)
+    v.SetDefault("rate_limit.key_by", []string{"user", "ip"})
+    v.SetDefault("rate_limit.api_key_header", "X-API-Key")
+    v.SetDefault("rate_limit.default", "100/1m")
+    v.SetDefault("rate_limit.routes", []string{})
+    v.SetDefault("rate_limit.methods", []string{})
+    v.SetDefault("cors.allow_origins", []string{"*"})
+    v.SetDefault("cors.allow_methods", []string{"GET", "POST", "HEAD", "PUT", "DELETE", "PATCH", "OPTIONS"})
+    v.SetDefault("cors.allow_headers", []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-API-Key"})
+    v.SetDefault("cors.expose_headers", []string{"X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"})
+    v.SetDefault("cors.allow_credentials", false)
+    v.SetDefault("cors.max_age", 600)
+    v.SetDefault("tracing.exporter", "none")
+    v.SetDefault("tracing.service_name", "go-starter-kit")
+    v.SetDefault("tracing.endpoint", "localhost:4317")
+    v.SetDefault("tracing.insecure", false)
+    v.SetDefault("tracing.file_path", "traces.jsonl")
+    v.SetDefault("tracing.sample_ratio", 1.0)
+    v.SetDefault("jwt.expire", 24)
+    v.SetDefault("mfa.issuer", "go-starter-kit")
+    v.SetDefault("mfa.required_roles", []string{})
+    v.SetDefault("mfa.pending_token_expire", 5)
+    v.SetDefault("mfa.recovery_code_count", 10)
+    v.SetDefault("mfa.max_failed_attempts", 5)
+    v.SetDefault("mfa.lockout", 15)
+    v.SetDefault("mail.driver", "log")
+    v.SetDefault("mail.from", "no-reply@localhost")
+    v.SetDefault("mail.port", "587")
+    v.SetDefault("mail.file_dir", "./tmp/mail")
+    v.SetDefault("verification.require_verified_email", false)
+    v.SetDefault("verification.token_expire", 24)
+    v.SetDefault("verification.url", "http://localhost:8080/verify-email")
+    v.SetDefault("log.level", "info")
+    v.SetDefault("log.format", "json")
+    v.SetDefault("log.redact_keys", []string{"password", "token", "secret", "authorization", "cookie"})
+    v.SetDefault("log.sampling.enabled", false)
+    v.SetDefault("log.sampling.initial", 100)
+    v.SetDefault("log.sampling.thereafter", 100)
+    v.SetDefault("log.sampling.interval", 1)
 }
@@ -0,0 +1,23 @@
+// Package configtest builds configurations in memory for tests, without
+// reading files or the environment.
+package configtest
+
+import "github.com/faizalnurrozi/go-starter-kit/internal/config"
+
+// Secret is the JWT secret of configurations built by New.
+const Secret = "configtest-secret-0123456789abcdef"
+
+// New returns the defaults completed into a valid configuration, then
+// applies each of mutate in order:
+//
+//	cfg := configtest.New(func(c *config.Config) { c.GRPC.Port = "0" })
+func New(mutate ...func(*config.Config)) *config.Config {
+	cfg := config.Default()
+	cfg.JWT.Secret = Secret
+	cfg.Database.Database = "test"
+
+	for _, m := range mutate {
+		m(cfg)
+	}
+	return cfg
+}
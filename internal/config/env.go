@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// defaultEnvPrefix prefixes the variable of every key, so database.host is
+// APP_DATABASE_HOST. CONFIG_ENV_PREFIX changes it; set it empty to use bare
+// names only.
+const defaultEnvPrefix = "APP"
+
+// configKey is a leaf setting of Config, such as database.host.
+type configKey struct {
+	name string
+	typ  reflect.Type
+}
+
+// configKeys returns every leaf key of Config, found from its mapstructure
+// tags so new fields are picked up without a separate list.
+func configKeys() []configKey {
+	var keys []configKey
+	var walk func(t reflect.Type, prefix string)
+	walk = func(t reflect.Type, prefix string) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			tag := field.Tag.Get("mapstructure")
+			if tag == "" {
+				continue
+			}
+			if field.Type.Kind() == reflect.Struct {
+				walk(field.Type, prefix+tag+".")
+				continue
+			}
+			keys = append(keys, configKey{name: prefix + tag, typ: field.Type})
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "")
+	return keys
+}
+
+// envPrefix returns the prefix of every variable, without the underscore.
+func envPrefix() string {
+	prefix, ok := os.LookupEnv("CONFIG_ENV_PREFIX")
+	if !ok {
+		return defaultEnvPrefix
+	}
+	return strings.ToUpper(prefix)
+}
+
+// envNames returns the variables that override key, in order of
+// precedence: the prefixed name, then the bare name kept for existing
+// deployments, e.g. APP_DATABASE_HOST then DATABASE_HOST.
+func envNames(key string) []string {
+	name := strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+	if prefix := envPrefix(); prefix != "" {
+		return []string{prefix + "_" + name, name}
+	}
+	return []string{name}
+}
+
+// bindEnv binds every key to its variables. Lists are comma-separated and
+// maps are comma-separated key=value pairs, e.g. APP_LOG_LEVELS=grpc=debug.
+func bindEnv(v *viper.Viper) error {
+	for _, key := range configKeys() {
+		if err := v.BindEnv(append([]string{key.name}, envNames(key.name)...)...); err != nil {
+			return err
+		}
+	}
+	return bindSecretFiles(v)
+}
+
+// setOverrides sets each key to its value, rejecting unknown keys so a
+// mistyped --set fails instead of being ignored.
+func setOverrides(v *viper.Viper, overrides map[string]string) error {
+	known := make(map[string]bool)
+	for _, key := range configKeys() {
+		known[key.name] = true
+	}
+
+	for key, value := range overrides {
+		if !known[key] {
+			return fmt.Errorf("unknown config key %q", key)
+		}
+		v.Set(key, value)
+	}
+	return nil
+}
+
+// decodeHook extends viper's default hooks to decode maps set from the
+// environment.
+func decodeHook() viper.DecoderConfigOption {
+	return viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+		stringToMapHook,
+	))
+}
+
+func stringToMapHook(from, to reflect.Type, data interface{}) (interface{}, error) {
+	if from.Kind() != reflect.String || to.Kind() != reflect.Map {
+		return data, nil
+	}
+
+	out := map[string]string{}
+	for _, pair := range strings.Split(data.(string), ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not a key=value pair", pair)
+		}
+		out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return out, nil
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Masked returns cfg as a map keyed like the YAML file, with passwords and
+// secrets replaced so the result is safe to print or log.
+func Masked(cfg *Config) map[string]interface{} {
+	return maskStruct(reflect.ValueOf(cfg).Elem())
+}
+
+func maskStruct(v reflect.Value) map[string]interface{} {
+	out := make(map[string]interface{}, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		key := v.Type().Field(i).Tag.Get("mapstructure")
+		if key == "" {
+			continue
+		}
+
+		field := v.Field(i)
+		switch {
+		case field.Type() == secretType:
+			out[key] = field.Interface().(Secret).String()
+		case field.Kind() == reflect.Struct:
+			out[key] = maskStruct(field)
+		default:
+			out[key] = field.Interface()
+		}
+	}
+	return out
+}
+
+// minSecretLength is the shortest jwt.secret accepted; HS256 keys shorter
+// than its 32-byte output are easy to brute-force.
+const minSecretLength = 32
+
+// Validate reports every setting that would stop the application from
+// working, joined into one error with one problem per line.
+func Validate(cfg *Config) error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(isPort(cfg.Server.Port), "server.port: %q is not a valid port", cfg.Server.Port)
+	check(cfg.Server.ShutdownTimeout > 0, "server.shutdown_timeout: must be positive")
+	check(cfg.Server.ShutdownDelay >= 0 && cfg.Server.ShutdownDelay < cfg.Server.ShutdownTimeout,
+		"server.shutdown_delay: must be at least 0 and less than shutdown_timeout")
+	check(cfg.Server.ProxyHeader == "" || len(cfg.Server.TrustedProxies) > 0,
+		"server.trusted_proxies: required when proxy_header is set, or any client could choose its address")
+	for _, proxy := range cfg.Server.TrustedProxies {
+		check(isIPOrCIDR(proxy), "server.trusted_proxies: %q is not an IP address or CIDR range", proxy)
+	}
+	check(isPort(cfg.GRPC.Port), "grpc.port: %q is not a valid port", cfg.GRPC.Port)
+	check(!cfg.Admin.Enabled || isPort(cfg.Admin.Port), "admin.port: %q is not a valid port", cfg.Admin.Port)
+
+	check(oneOf(cfg.Database.Driver, "postgres", "mysql"), "database.driver: %q is not postgres or mysql", cfg.Database.Driver)
+	check(cfg.Database.Host != "", "database.host: must be set")
+	check(isPort(cfg.Database.Port), "database.port: %q is not a valid port", cfg.Database.Port)
+	check(cfg.Database.Database != "", "database.database: must be set")
+
+	check(oneOf(cfg.Cache.Driver, "redis", "tiered", "memory", "none", ""), "cache.driver: %q is not redis, tiered, memory or none", cfg.Cache.Driver)
+	check(cfg.Cache.TTL >= 0, "cache.ttl: must not be negative")
+	check(cfg.Cache.TTLJitter >= 0 && cfg.Cache.TTLJitter <= 1, "cache.ttl_jitter: %v is not between 0 and 1", cfg.Cache.TTLJitter)
+	check(!cfg.HTTPCache.ListCache || cfg.HTTPCache.ListCacheTTL > 0, "http_cache.list_cache_ttl: must be positive when list_cache is enabled")
+
+	check(oneOf(cfg.Redis.Mode, "standalone", "sentinel", "cluster", ""), "redis.mode: %q is not standalone, sentinel or cluster", cfg.Redis.Mode)
+	if cfg.Redis.Mode == "sentinel" {
+		check(cfg.Redis.MasterName != "", "redis.master_name: must be set in sentinel mode")
+		check(len(cfg.Redis.Addrs) > 0, "redis.addrs: must list the sentinels in sentinel mode")
+	}
+	if cfg.Redis.Mode == "cluster" {
+		check(len(cfg.Redis.Addrs) > 0, "redis.addrs: must list the cluster nodes in cluster mode")
+	}
+	check((cfg.Redis.TLS.CertFile == "") == (cfg.Redis.TLS.KeyFile == ""), "redis.tls: cert_file and key_file must be set together")
+
+	check(cfg.JWT.Secret != "", "jwt.secret: must be set (APP_JWT_SECRET or APP_JWT_SECRET_FILE)")
+	check(cfg.JWT.Secret == "" || len(cfg.JWT.Secret) >= minSecretLength, "jwt.secret: must be at least %d characters", minSecretLength)
+	check(cfg.JWT.Expire > 0, "jwt.expire: must be positive")
+	check(cfg.MFA.PendingTokenExpire > 0, "mfa.pending_token_expire: must be positive")
+	check(cfg.MFA.RecoveryCodeCount > 0, "mfa.recovery_code_count: must be positive")
+	check(cfg.MFA.MaxFailedAttempts > 0, "mfa.max_failed_attempts: must be positive")
+	check(cfg.MFA.Lockout > 0, "mfa.lockout: must be positive")
+	check(cfg.Verification.TokenExpire > 0, "verification.token_expire: must be positive")
+
+	check(oneOf(cfg.Mail.Driver, "log", "smtp", "file", ""), "mail.driver: %q is not log, smtp or file", cfg.Mail.Driver)
+	check(cfg.Mail.Driver != "smtp" || cfg.Mail.Host != "", "mail.host: must be set for the smtp driver")
+
+	check(isLogLevel(cfg.Log.Level), "log.level: %q is not a known level", cfg.Log.Level)
+	check(oneOf(cfg.Log.Format, "json", "text", ""), "log.format: %q is not json or text", cfg.Log.Format)
+	for _, origin := range cfg.CORS.AllowOrigins {
+		check(isOriginPattern(origin), "cors.allow_origins: %q is not *, scheme://host[:port] or scheme://*.domain", origin)
+		check(origin != "*" || !cfg.CORS.AllowCredentials, "cors.allow_origins: \"*\" cannot be combined with allow_credentials; list the origins instead")
+	}
+	check(cfg.CORS.MaxAge >= 0, "cors.max_age: must not be negative")
+
+	check(oneOf(cfg.Tracing.Exporter, "otlp", "stdout", "file", "none", ""), "tracing.exporter: %q is not otlp, stdout, file or none", cfg.Tracing.Exporter)
+	check(cfg.Tracing.SampleRatio >= 0 && cfg.Tracing.SampleRatio <= 1, "tracing.sample_ratio: %v is not between 0 and 1", cfg.Tracing.SampleRatio)
+	check(cfg.Health.Timeout > 0, "health.timeout: must be positive")
+
+	check(oneOf(cfg.RateLimit.Driver, "redis", "memory"), "rate_limit.driver: %q is not redis or memory", cfg.RateLimit.Driver)
+	for _, k := range cfg.RateLimit.KeyBy {
+		check(oneOf(k, "api_key", "user", "ip"), "rate_limit.key_by: %q is not api_key, user or ip", k)
+	}
+	check(!cfg.RateLimit.Enabled || len(cfg.RateLimit.KeyBy) > 0, "rate_limit.key_by: must not be empty")
+	_, _, err := ParseRateLimit(cfg.RateLimit.Default)
+	check(err == nil, "rate_limit.default: %v", err)
+	for _, entry := range cfg.RateLimit.Routes {
+		_, _, _, err := ParseRateLimitEntry(entry)
+		check(err == nil, "rate_limit.routes: %v", err)
+	}
+	for _, entry := range cfg.RateLimit.Methods {
+		_, _, _, err := ParseRateLimitEntry(entry)
+		check(err == nil, "rate_limit.methods: %v", err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ParseRateLimit parses a rate limit rule such as "100/1m" into the
+// requests allowed per window. An empty rule or "off" means no limit and
+// returns zero requests.
+func ParseRateLimit(rule string) (requests int, window time.Duration, err error) {
+	if rule == "" || rule == "off" {
+		return 0, 0, nil
+	}
+
+	count, per, ok := strings.Cut(rule, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("%q is not <requests>/<window>, e.g. 100/1m", rule)
+	}
+	requests, err = strconv.Atoi(count)
+	if err != nil || requests <= 0 {
+		return 0, 0, fmt.Errorf("%q: requests must be a positive number", rule)
+	}
+	window, err = time.ParseDuration(per)
+	if err != nil || window < time.Second {
+		return 0, 0, fmt.Errorf("%q: window must be a duration of at least 1s", rule)
+	}
+	return requests, window, nil
+}
+
+// ParseRateLimitEntry parses a "<pattern> <rule>" entry of rate_limit.routes
+// or rate_limit.methods, such as "POST /api/v1/auth/login 5/1m".
+func ParseRateLimitEntry(entry string) (pattern string, requests int, window time.Duration, err error) {
+	entry = strings.TrimSpace(entry)
+	i := strings.LastIndexByte(entry, ' ')
+	if i < 0 {
+		return "", 0, 0, fmt.Errorf("%q is not <pattern> <rule>", entry)
+	}
+	pattern = strings.TrimSpace(entry[:i])
+	requests, window, err = ParseRateLimit(entry[i+1:])
+	return pattern, requests, window, err
+}
+
+// isOriginPattern reports whether s is "*", an origin such as
+// "https://app.example.com:8443", or one whose host starts with a "*."
+// wildcard such as "https://*.example.com".
+func isOriginPattern(s string) bool {
+	if s == "*" {
+		return true
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || u.User != nil {
+		return false
+	}
+	host := strings.TrimPrefix(u.Hostname(), "*.")
+	return host != "" && !strings.Contains(host, "*")
+}
+
+func isPort(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0 && n <= 65535
+}
+
+func isIPOrCIDR(s string) bool {
+	if _, _, err := net.ParseCIDR(s); err == nil {
+		return true
+	}
+	return net.ParseIP(s) != nil
+}
+
+func isLogLevel(s string) bool {
+	if s == "" || s == "silent" {
+		return true
+	}
+	_, err := logrus.ParseLevel(s)
+	return err == nil
+}
+
+func oneOf(s string, values ...string) bool {
+	for _, v := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
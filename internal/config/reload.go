@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// Watcher keeps the configuration current as its files change. Only keys
+// tagged reload:"safe", such as log.level, take effect without a restart;
+// changes to any other key are rejected and the running value is kept.
+type Watcher struct {
+	opts        options
+	current     atomic.Pointer[Config]
+	mu          sync.Mutex
+	subscribers []func(old, new *Config)
+}
+
+// NewWatcher returns a Watcher starting from cfg, which must have been
+// loaded with the same opts.
+func NewWatcher(cfg *Config, opts ...Option) *Watcher {
+	w := &Watcher{opts: newOptions(opts)}
+	w.current.Store(cfg)
+	return w
+}
+
+// Current returns the configuration with every reload applied so far.
+func (w *Watcher) Current() *Config {
+	return w.current.Load()
+}
+
+// Subscribe calls fn with the old and new value of a section whenever a
+// reload changes it:
+//
+//	config.Subscribe(w, func(c *config.Config) config.LogConfig { return c.Log }, apply)
+func Subscribe[T any](w *Watcher, section func(*Config) T, fn func(old, new T)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.subscribers = append(w.subscribers, func(old, new *Config) {
+		o, n := section(old), section(new)
+		if !reflect.DeepEqual(o, n) {
+			fn(o, n)
+		}
+	})
+}
+
+// Reload reads the configuration again and applies its safe changes. An
+// invalid configuration, or one made invalid by keeping the running values
+// of restart-only keys, is rejected whole; otherwise safe changes apply and
+// the error names the keys that need a restart.
+func (w *Watcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	next, _, err := read(w.opts)
+	if err != nil {
+		return err
+	}
+	if err := Validate(next); err != nil {
+		return fmt.Errorf("invalid configuration, keeping the running one:\n%w", err)
+	}
+
+	old := w.current.Load()
+	applied := *old
+	rejected := mergeSafe(reflect.ValueOf(&applied).Elem(), reflect.ValueOf(next).Elem(), "", false)
+	// Safe changes can still clash with the running values they join
+	if err := Validate(&applied); err != nil {
+		return fmt.Errorf("invalid configuration once restart-only changes are left out, keeping the running one:\n%w", err)
+	}
+
+	if !reflect.DeepEqual(old, &applied) {
+		w.current.Store(&applied)
+		for _, notify := range w.subscribers {
+			notify(old, &applied)
+		}
+	}
+
+	if len(rejected) > 0 {
+		return fmt.Errorf("restart required to change %s; keeping the running values", strings.Join(rejected, ", "))
+	}
+	return nil
+}
+
+// Start watches the files the configuration was read from, reloading on
+// every change and passing reload errors to onError. Watching lasts for
+// the life of the process.
+func (w *Watcher) Start(onError func(error)) error {
+	_, files, err := read(w.opts)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		v := viper.New()
+		v.SetConfigFile(file)
+		v.OnConfigChange(func(fsnotify.Event) {
+			if err := w.Reload(); err != nil {
+				onError(err)
+			}
+		})
+		v.WatchConfig()
+	}
+	return nil
+}
+
+// mergeSafe copies the safe keys that differ in next into applied, and
+// returns the keys that differ but are not safe. Tagging a section safe
+// makes all of its keys safe.
+func mergeSafe(applied, next reflect.Value, prefix string, safe bool) []string {
+	var rejected []string
+	for i := 0; i < applied.NumField(); i++ {
+		field := applied.Type().Field(i)
+		key := prefix + field.Tag.Get("mapstructure")
+		fieldSafe := safe || field.Tag.Get("reload") == "safe"
+
+		if field.Type.Kind() == reflect.Struct {
+			rejected = append(rejected, mergeSafe(applied.Field(i), next.Field(i), key+".", fieldSafe)...)
+			continue
+		}
+		if reflect.DeepEqual(applied.Field(i).Interface(), next.Field(i).Interface()) {
+			continue
+		}
+
+		if fieldSafe {
+			applied.Field(i).Set(next.Field(i))
+		} else {
+			rejected = append(rejected, key)
+		}
+	}
+	return rejected
+}
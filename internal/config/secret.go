@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// masked replaces secret values wherever configuration is printed.
+const masked = "******"
+
+// Secret is a configuration string that never prints its value: fmt, JSON
+// and YAML show a mask instead, so a logged or printed Config is safe. Use
+// Value for the real string.
+type Secret string
+
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return masked
+}
+
+func (s Secret) GoString() string {
+	return strconv.Quote(s.String())
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+var secretType = reflect.TypeOf(Secret(""))
+
+// bindSecretFiles reads every secret whose variable has a _FILE suffix,
+// e.g. APP_JWT_SECRET_FILE or JWT_SECRET_FILE, from the file it names, such
+// as a mounted Docker or Kubernetes secret.
+func bindSecretFiles(v *viper.Viper) error {
+	for _, key := range configKeys() {
+		if key.typ != secretType {
+			continue
+		}
+		for _, name := range envNames(key.name) {
+			path := os.Getenv(name + "_FILE")
+			if path == "" {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("%s_FILE: %w", name, err)
+			}
+			v.Set(key.name, strings.TrimRight(string(data), "\r\n"))
+			break
+		}
+	}
+	return nil
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/faizalnurrozi/go-starter-kit/internal/config"
 	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/metrics"
+	"github.com/faizalnurrozi/go-starter-kit/internal/tracing"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
@@ -20,7 +22,7 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 		dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
 			cfg.Database.Host,
 			cfg.Database.Username,
-			cfg.Database.Password,
+			cfg.Database.Password.Value(),
 			cfg.Database.Database,
 			cfg.Database.Port,
 			cfg.Database.SSLMode,
@@ -29,7 +31,7 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 	case "mysql":
 		dsn = fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 			cfg.Database.Username,
-			cfg.Database.Password,
+			cfg.Database.Password.Value(),
 			cfg.Database.Host,
 			cfg.Database.Port,
 			cfg.Database.Database,
@@ -39,19 +41,28 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Database.Driver)
 	}
 
-	db, err := gorm.Open(dialector, &gorm.Config{})
+	// TranslateError maps driver-specific errors such as unique violations
+	// to gorm.ErrDuplicatedKey so services can handle them portably.
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
 	if err != nil {
 		return nil, err
 	}
 
-	// Auto migrate
-	if err := db.AutoMigrate(&entity.User{}); err != nil {
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		return nil, err
+	}
+	if err := db.Use(tracing.GormPlugin{}); err != nil {
 		return nil, err
 	}
 
 	return db, nil
 }
 
+// Migrate creates or updates the tables for every entity.
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&entity.User{}, &entity.RecoveryCode{}, &entity.AuditEvent{})
+}
+
 func Close(db *gorm.DB) error {
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -0,0 +1,16 @@
+package dto
+
+// ListAuditEventsRequest holds the query filters for GET /audit-events.
+// From and To are RFC 3339 timestamps; To is exclusive.
+type ListAuditEventsRequest struct {
+	ActorType  string `query:"actor_type" validate:"omitempty,max=32"`
+	ActorID    string `query:"actor_id" validate:"omitempty,max=64"`
+	Action     string `query:"action" validate:"omitempty,max=64"`
+	TargetType string `query:"target_type" validate:"omitempty,max=64"`
+	TargetID   string `query:"target_id" validate:"omitempty,max=64"`
+	RequestID  string `query:"request_id" validate:"omitempty,max=64"`
+	From       string `query:"from" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To         string `query:"to" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Limit      int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset     int    `query:"offset" validate:"omitempty,min=0"`
+}
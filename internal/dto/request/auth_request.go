@@ -0,0 +1,30 @@
+package dto
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+// VerifyMFARequest completes a login that returned an MFA pending token,
+// using either a current TOTP code or one of the user's recovery codes.
+type VerifyMFARequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code,omitempty" validate:"required_without=RecoveryCode,omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code,omitempty" validate:"required_without=Code"`
+}
+
+type TOTPCodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" validate:"required"`
+}
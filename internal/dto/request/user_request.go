@@ -6,12 +6,25 @@ type CreateUserRequest struct {
     Password string `json:"password" validate:"required,min=6"`
 }
 
+// SetPasswordRequest replaces a user's password from the command line.
+type SetPasswordRequest struct {
+    Email    string `validate:"required,email"`
+    Password string `validate:"required,min=6"`
+}
+
 type UpdateUserRequest struct {
     Name     *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
     Email    *string `json:"email,omitempty" validate:"omitempty,email"`
     IsActive *bool   `json:"is_active,omitempty"`
 }
 
+// UpdateCurrentUserRequest is the self-service subset of UpdateUserRequest;
+// users cannot change their own active flag.
+type UpdateCurrentUserRequest struct {
+    Name  *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+    Email *string `json:"email,omitempty" validate:"omitempty,email"`
+}
+
 type GetUserParams struct {
     ID uint `params:"id" validate:"required,min=1"`
 }
\ No newline at end of file
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+)
+
+type AuditActor struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+}
+
+type AuditTarget struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+type AuditEventResponse struct {
+	ID        uint            `json:"id"`
+	Actor     AuditActor      `json:"actor"`
+	Action    string          `json:"action"`
+	Target    AuditTarget     `json:"target"`
+	Changes   json.RawMessage `json:"changes,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	IP        string          `json:"ip,omitempty"`
+	UserAgent string          `json:"user_agent,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AuditEventListResponse struct {
+	Events []*AuditEventResponse `json:"events"`
+	Total  int64                 `json:"total"`
+	Limit  int                   `json:"limit"`
+	Offset int                   `json:"offset"`
+}
+
+func NewAuditEventResponse(event *entity.AuditEvent) *AuditEventResponse {
+	var changes json.RawMessage
+	if event.Changes != "" {
+		changes = json.RawMessage(event.Changes)
+	}
+
+	return &AuditEventResponse{
+		ID:        event.ID,
+		Actor:     AuditActor{Type: event.ActorType, ID: event.ActorID},
+		Action:    event.Action,
+		Target:    AuditTarget{Type: event.TargetType, ID: event.TargetID},
+		Changes:   changes,
+		RequestID: event.RequestID,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+func NewAuditEventListResponse(events []entity.AuditEvent, total int64, limit, offset int) *AuditEventListResponse {
+	response := make([]*AuditEventResponse, len(events))
+	for i := range events {
+		response[i] = NewAuditEventResponse(&events[i])
+	}
+	return &AuditEventListResponse{
+		Events: response,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+}
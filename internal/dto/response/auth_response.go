@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+// LoginResponse carries either an access token or, when a second factor is
+// needed, a short-lived MFA token to present to the verify/enroll endpoints.
+type LoginResponse struct {
+	AccessToken           string     `json:"access_token,omitempty"`
+	TokenType             string     `json:"token_type,omitempty"`
+	ExpiresAt             *time.Time `json:"expires_at,omitempty"`
+	MFARequired           bool       `json:"mfa_required"`
+	MFAEnrollmentRequired bool       `json:"mfa_enrollment_required,omitempty"`
+	MFAToken              string     `json:"mfa_token,omitempty"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// AuditEvent is an append-only record of a mutation. Changes holds a JSON
+// object mapping each changed field to its before/after values, with secret
+// fields redacted.
+type AuditEvent struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	ActorType  string    `json:"actor_type" gorm:"size:32;not null"`
+	ActorID    string    `json:"actor_id" gorm:"size:64;index"`
+	Action     string    `json:"action" gorm:"size:64;not null;index"`
+	TargetType string    `json:"target_type" gorm:"size:64;not null;index:idx_audit_events_target"`
+	TargetID   string    `json:"target_id" gorm:"size:64;index:idx_audit_events_target"`
+	Changes    string    `json:"changes" gorm:"type:text"`
+	RequestID  string    `json:"request_id" gorm:"size:64;index"`
+	IP         string    `json:"ip" gorm:"size:45"`
+	UserAgent  string    `json:"user_agent" gorm:"size:255"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// RecoveryCode is a single-use fallback for a user's TOTP second factor.
+// Only the SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primarykey"`
+	UserID    uint       `json:"user_id" gorm:"index;not null"`
+	CodeHash  string     `json:"-" gorm:"size:64;not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
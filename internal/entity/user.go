@@ -5,13 +5,61 @@ import (
     "gorm.io/gorm"
 )
 
+const (
+    RoleUser  = "user"
+    RoleAdmin = "admin"
+)
+
 type User struct {
     ID        uint           `json:"id" gorm:"primarykey"`
     Name      string         `json:"name" gorm:"not null"`
     Email     string         `json:"email" gorm:"unique;not null"`
     Password  string         `json:"-" gorm:"not null"`
+    Role      string         `json:"role" gorm:"size:32;not null;default:user"`
     IsActive  bool           `json:"is_active" gorm:"default:true"`
     CreatedAt time.Time      `json:"created_at"`
     UpdatedAt time.Time      `json:"updated_at"`
     DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+    // Email verification. A requested address change is held in
+    // PendingEmail until confirmed from that address; Email keeps working
+    // in the meantime.
+    EmailVerifiedAt *time.Time `json:"email_verified_at"`
+    PendingEmail    string     `json:"pending_email,omitempty" gorm:"size:255"`
+
+    // TOTP two-factor authentication. The secret is set on enrollment and
+    // only becomes effective once TOTPEnabledAt is set by a confirmed code.
+    TOTPSecret    string     `json:"-" gorm:"column:totp_secret;size:64"`
+    TOTPEnabledAt *time.Time `json:"-" gorm:"column:totp_enabled_at"`
+    TOTPLastStep  int64      `json:"-" gorm:"column:totp_last_step;not null;default:0"`
+
+    // Wrong codes at sign-in since the last success; reaching the limit
+    // refuses verification until MFALockedUntil.
+    MFAFailedAttempts int        `json:"-" gorm:"column:mfa_failed_attempts;not null;default:0"`
+    MFALockedUntil    *time.Time `json:"-" gorm:"column:mfa_locked_until"`
+}
+
+// MFAEnabled reports whether the user has a confirmed TOTP enrollment.
+func (u *User) MFAEnabled() bool {
+    return u.TOTPEnabledAt != nil && u.TOTPSecret != ""
+}
+
+// MFALocked reports whether second-factor codes are refused at now after
+// too many wrong ones.
+func (u *User) MFALocked(now time.Time) bool {
+    return u.MFALockedUntil != nil && now.Before(*u.MFALockedUntil)
+}
+
+// EmailVerified reports whether the current email address has been confirmed.
+func (u *User) EmailVerified() bool {
+    return u.EmailVerifiedAt != nil
+}
+
+// ClearMFA removes any TOTP enrollment, confirmed or pending.
+func (u *User) ClearMFA() {
+    u.TOTPSecret = ""
+    u.TOTPEnabledAt = nil
+    u.TOTPLastStep = 0
+    u.MFAFailedAttempts = 0
+    u.MFALockedUntil = nil
 }
@@ -3,6 +3,9 @@ package errors
 import (
 	"fmt"
 	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type AppError struct {
@@ -15,6 +18,40 @@ func (e *AppError) Error() string {
 	return e.Message
 }
 
+// GRPCStatus converts e to the gRPC status with the code closest to its
+// HTTP status, so gRPC clients, metrics and traces see NotFound rather than
+// Unknown.
+func (e *AppError) GRPCStatus() *status.Status {
+	return status.New(grpcCode(e.Code), e.Message)
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if httpStatus >= 500 {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
 func NewAppError(code int, message string, details ...string) *AppError {
 	err := &AppError{
 		Code:    code,
@@ -39,6 +76,18 @@ func NewUnauthorizedError() *AppError {
 	return NewAppError(http.StatusUnauthorized, "Unauthorized")
 }
 
+func NewForbiddenError(message string) *AppError {
+	return NewAppError(http.StatusForbidden, message)
+}
+
+func NewConflictError(message string) *AppError {
+	return NewAppError(http.StatusConflict, message)
+}
+
+func NewTooManyRequestsError() *AppError {
+	return NewAppError(http.StatusTooManyRequests, "Too many requests")
+}
+
 func NewInternalError(message string) *AppError {
 	return NewAppError(http.StatusInternalServerError, message)
 }
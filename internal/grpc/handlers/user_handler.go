@@ -4,9 +4,13 @@ import (
 	"context"
 	"time"
 
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
 	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
 	"github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
 	pb "github.com/faizalnurrozi/go-starter-kit/proto/user"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type userHandler struct {
@@ -23,6 +27,10 @@ func NewUserHandler(userService interfaces.UserService) pb.UserServiceServer {
 
 // CreateUser implements pb.UserServiceServer
 func (h *userHandler) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.UserResponse, error) {
+	if err := requireAuthentication(ctx); err != nil {
+		return nil, err
+	}
+
 	// Simulasi: Kamu bisa panggil h.userService.Create jika sudah tersedia
 	dtoReq := &dto.CreateUserRequest{
 		Name:     req.Name,
@@ -67,8 +75,35 @@ func (h *userHandler) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.
 	}, nil
 }
 
+// GetCurrentUser implements pb.UserServiceServer
+func (h *userHandler) GetCurrentUser(ctx context.Context, req *pb.GetCurrentUserRequest) (*pb.UserResponse, error) {
+	// Principal di-set oleh auth interceptor dari metadata "authorization"
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+
+	user, err := h.userService.GetByID(ctx, principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.UserResponse{
+		Id:        uint32(user.ID),
+		Name:      user.Name,
+		Email:     user.Email,
+		IsActive:  user.IsActive,
+		CreatedAt: user.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
 // UpdateUser implements pb.UserServiceServer
 func (h *userHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest) (*pb.UserResponse, error) {
+	if err := requireAuthentication(ctx); err != nil {
+		return nil, err
+	}
+
 	// Konversi request dari proto ke DTO
 	dtoReq := &dto.UpdateUserRequest{
 		Name:     req.Name,
@@ -96,6 +131,10 @@ func (h *userHandler) UpdateUser(ctx context.Context, req *pb.UpdateUserRequest)
 
 // DeleteUser implements pb.UserServiceServer
 func (h *userHandler) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	if err := requireAuthentication(ctx); err != nil {
+		return nil, err
+	}
+
 	err := h.userService.Delete(ctx, uint(req.Id))
 	if err != nil {
 		return nil, err
@@ -131,3 +170,13 @@ func (h *userHandler) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (
 		Total: int32(len(pbUsers)), // bisa diganti kalau ada total dari service
 	}, nil
 }
+
+// requireAuthentication refuses calls without a bearer token. The auth
+// interceptor lets them through so reads can stay public; mutations must
+// not.
+func requireAuthentication(ctx context.Context) error {
+	if _, ok := auth.PrincipalFromContext(ctx); !ok {
+		return status.Error(codes.Unauthenticated, "authentication required")
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/metrics"
+	"github.com/faizalnurrozi/go-starter-kit/internal/ratelimit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/requestid"
+	"github.com/faizalnurrozi/go-starter-kit/internal/tracing"
+
+	"go.opentelemetry.io/otel"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var log = logger.Named("grpc")
+
+// tracingUnaryInterceptor starts a server span for each call, continuing the
+// trace from incoming traceparent metadata, mirroring middleware.Tracing.
+func tracingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			ctx = otel.GetTextMapPropagator().Extract(ctx, tracing.MetadataCarrier(md))
+		}
+
+		service, method, _ := strings.Cut(strings.TrimPrefix(info.FullMethod, "/"), "/")
+		ctx, span := tracing.Tracer().Start(ctx, strings.TrimPrefix(info.FullMethod, "/"),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.RPCSystemGRPC,
+				semconv.RPCService(service),
+				semconv.RPCMethod(method),
+			),
+		)
+		defer span.End()
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+		}
+
+		return resp, err
+	}
+}
+
+// metricsUnaryInterceptor records call counts and latency per method and
+// status code, mirroring middleware.Metrics for HTTP.
+func metricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err).String()
+		metrics.GRPCRequests.WithLabelValues(info.FullMethod, code).Inc()
+		metrics.GRPCDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+
+		return resp, err
+	}
+}
+
+// authUnaryInterceptor resolves the bearer token in the "authorization"
+// metadata into an auth.Principal on the context. Calls without a token pass
+// through unauthenticated; handlers that need a caller check for the
+// principal themselves. A token that is present but invalid is rejected.
+func authUnaryInterceptor(secret string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return handler(ctx, req)
+		}
+
+		tokenString := strings.TrimPrefix(values[0], "Bearer ")
+		claims, err := auth.ParseToken(secret, tokenString)
+		if err != nil || claims.Purpose != auth.PurposeAccess {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		return handler(auth.WithPrincipal(ctx, auth.NewPrincipal(claims)), req)
+	}
+}
+
+// requestIDUnaryInterceptor accepts the caller's x-request-id metadata or
+// generates one, stores it in the context and returns it in the response
+// header, mirroring middleware.RequestID for HTTP.
+func requestIDUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(requestid.Header); len(values) > 0 {
+				id = values[0]
+			}
+		}
+		id = requestid.Resolve(id)
+
+		if err := grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, id)); err != nil {
+			log.Warn(ctx, "Error setting request ID header", logger.Err(err))
+		}
+
+		return handler(requestid.WithID(ctx, id), req)
+	}
+}
+
+// auditUnaryInterceptor records the request details stored on audit events,
+// mirroring middleware.AuditMetadata for HTTP.
+func auditUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		meta := &audit.Metadata{RequestID: requestid.FromContext(ctx), IP: peerIP(ctx)}
+
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("user-agent"); len(values) > 0 {
+				meta.UserAgent = values[0]
+			}
+		}
+
+		return handler(audit.WithMetadata(ctx, meta), req)
+	}
+}
+
+// rateLimitUnaryInterceptor rejects calls over the limit with
+// ResourceExhausted, mirroring middleware.RateLimit. It runs after
+// authUnaryInterceptor so callers can be keyed by user. Limits are sent as
+// ratelimit-* and retry-after response headers.
+func rateLimitUnaryInterceptor(limiter *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		client := ratelimit.Client{IP: peerIP(ctx)}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(limiter.APIKeyHeader()); len(values) > 0 {
+				client.APIKey = values[0]
+			}
+		}
+		if principal, ok := auth.PrincipalFromContext(ctx); ok {
+			client.UserID = principal.UserID
+		}
+
+		result, limited := limiter.TakeGRPC(ctx, info.FullMethod, client)
+		if !limited {
+			return handler(ctx, req)
+		}
+
+		md := metadata.MD{}
+		for name, value := range result.Headers() {
+			md.Set(name, value)
+		}
+		if err := grpc.SetHeader(ctx, md); err != nil {
+			log.Warn(ctx, "Error setting rate limit headers", logger.Err(err))
+		}
+		if !result.Allowed {
+			return nil, status.Error(codes.ResourceExhausted, "too many requests")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// peerIP returns the caller's address without its port.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		return host
+	}
+	return ip
+}
@@ -1,60 +1,127 @@
 package grpc
 
 import (
+	"context"
+	"errors"
 	"net"
+	"sync"
 
 	"github.com/faizalnurrozi/go-starter-kit/internal/config"
 	"github.com/faizalnurrozi/go-starter-kit/internal/grpc/handlers"
 	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
-	repository_impl "github.com/faizalnurrozi/go-starter-kit/internal/repository/impl"
-	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
+	"github.com/faizalnurrozi/go-starter-kit/internal/ratelimit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
 	pb "github.com/faizalnurrozi/go-starter-kit/proto/user"
-	"github.com/faizalnurrozi/go-starter-kit/internal/database"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type Server struct {
 	server *grpc.Server
-	config *config.Config
-}
+	health *health.Server
+	config config.GRPCConfig
 
-func NewServer(cfg *config.Config) *Server {
-	grpcServer := grpc.NewServer()
+	mu       sync.Mutex
+	listener net.Listener
+}
 
-	// Initialize database
-	db, err := database.Connect(cfg)
-	if err != nil {
-		logger.Fatal("Failed to connect database:", err)
+// NewServer serves userService, sharing the HTTP server's services so both
+// use one database pool and cache. A nil limiter disables rate limiting.
+func NewServer(cfg config.GRPCConfig, jwtConfig config.JWTConfig, limiter *ratelimit.Limiter, userService interfaces.UserService) *Server {
+	interceptors := []grpc.UnaryServerInterceptor{
+		tracingUnaryInterceptor(),
+		requestIDUnaryInterceptor(),
+		metricsUnaryInterceptor(),
+		auditUnaryInterceptor(),
+		authUnaryInterceptor(jwtConfig.Secret.Value()),
 	}
-
-	// Inisialisasi dependencies
-	userRepo := repository_impl.NewUserRepository(db)
-	userService := serviceimpl.NewUserService(userRepo, nil)
-	userHandler := handlers.NewUserHandler(userService)
+	if limiter != nil {
+		interceptors = append(interceptors, rateLimitUnaryInterceptor(limiter))
+	}
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
 
 	// Registrasi handler
-	pb.RegisterUserServiceServer(grpcServer, userHandler)
+	pb.RegisterUserServiceServer(grpcServer, handlers.NewUserHandler(userService))
+
+	// Standard grpc.health.v1 service for load balancers and probes
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(pb.UserService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
 
 	return &Server{
 		server: grpcServer,
+		health: healthServer,
 		config: cfg,
 	}
 }
 
-func (s *Server) Start() {
-	lis, err := net.Listen("tcp", ":"+s.config.GRPC.Port)
+// Listen binds the gRPC port, so a taken port fails startup.
+func (s *Server) Listen() error {
+	lis, err := net.Listen("tcp", ":"+s.config.Port)
 	if err != nil {
-		logger.Fatal("Failed to listen for gRPC:", err)
+		return err
 	}
 
-	logger.Info("gRPC server listening on port " + s.config.GRPC.Port)
+	s.mu.Lock()
+	s.listener = lis
+	s.mu.Unlock()
+
+	logger.Info("gRPC server listening on port " + s.config.Port)
+	return nil
+}
 
-	if err := s.server.Serve(lis); err != nil {
-		logger.Fatal("Failed to serve gRPC:", err)
+// Serve blocks until the server stops; it returns nil after Stop.
+func (s *Server) Serve() error {
+	s.mu.Lock()
+	lis := s.listener
+	s.mu.Unlock()
+	if lis == nil {
+		return errors.New("gRPC server is not listening")
 	}
+	return s.server.Serve(lis)
 }
 
-func (s *Server) Stop() {
-	s.server.GracefulStop()
+// Check connects to the gRPC listener, for readiness checks.
+func (s *Server) Check(ctx context.Context) error {
+	s.mu.Lock()
+	lis := s.listener
+	s.mu.Unlock()
+	if lis == nil {
+		return errors.New("gRPC server is not listening")
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, lis.Addr().Network(), lis.Addr().String())
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// Drain reports NOT_SERVING on the health service so clients move away
+// before the server stops.
+func (s *Server) Drain() {
+	s.health.Shutdown()
+}
+
+// Stop waits for in-flight calls to finish, closing them forcibly once ctx
+// is done.
+func (s *Server) Stop(ctx context.Context) error {
+	s.health.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
 }
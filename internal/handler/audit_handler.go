@@ -0,0 +1,30 @@
+package handler
+
+import (
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
+	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditHandler struct {
+	auditService interfaces.AuditService
+}
+
+func NewAuditHandler(auditService interfaces.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) List(c *fiber.Ctx) error {
+	req := c.Locals("validatedQuery").(*dto.ListAuditEventsRequest)
+
+	events, err := h.auditService.List(c.UserContext(), req)
+	if err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, events)
+}
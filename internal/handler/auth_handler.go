@@ -0,0 +1,73 @@
+package handler
+
+import (
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
+	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuthHandler struct {
+	authService         interfaces.AuthService
+	verificationService interfaces.EmailVerificationService
+}
+
+func NewAuthHandler(authService interfaces.AuthService, verificationService interfaces.EmailVerificationService) *AuthHandler {
+	return &AuthHandler{
+		authService:         authService,
+		verificationService: verificationService,
+	}
+}
+
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	req := c.Locals("validatedRequest").(*dto.LoginRequest)
+
+	result, err := h.authService.Login(c.UserContext(), req)
+	if err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, result)
+}
+
+func (h *AuthHandler) VerifyMFA(c *fiber.Ctx) error {
+	req := c.Locals("validatedRequest").(*dto.VerifyMFARequest)
+
+	result, err := h.authService.VerifyMFA(c.UserContext(), req)
+	if err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, result)
+}
+
+func (h *AuthHandler) VerifyEmail(c *fiber.Ctx) error {
+	req := c.Locals("validatedRequest").(*dto.VerifyEmailRequest)
+
+	if err := h.verificationService.VerifyEmail(c.UserContext(), req); err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, map[string]string{"message": "Email verified successfully"})
+}
+
+func (h *AuthHandler) ResendVerification(c *fiber.Ctx) error {
+	req := c.Locals("validatedRequest").(*dto.ResendVerificationRequest)
+
+	if err := h.verificationService.ResendVerification(c.UserContext(), req); err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, map[string]string{"message": "If the account exists and is unverified, a new verification email has been sent"})
+}
+
+func (h *AuthHandler) ConfirmEmailChange(c *fiber.Ctx) error {
+	req := c.Locals("validatedRequest").(*dto.ConfirmEmailChangeRequest)
+
+	if err := h.verificationService.ConfirmEmailChange(c.UserContext(), req); err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, map[string]string{"message": "Email changed successfully"})
+}
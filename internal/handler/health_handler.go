@@ -1,23 +1,85 @@
 package handler
 
 import (
+	"github.com/faizalnurrozi/go-starter-kit/internal/buildinfo"
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/health"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
 	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-type HealthHandler struct{}
+var log = logger.Named("handler")
 
-func NewHealthHandler() *HealthHandler {
-	return &HealthHandler{}
+type HealthHandler struct {
+	checker *health.Checker
+	cache   cache.Cache
 }
 
+func NewHealthHandler(checker *health.Checker, c cache.Cache) *HealthHandler {
+	return &HealthHandler{checker: checker, cache: c}
+}
+
+// Live reports that the process is running. It checks no dependencies, so
+// a failing database does not get the process restarted.
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return utils.SendSuccess(c, map[string]interface{}{
+		"status": health.StatusUp,
+	})
+}
+
+// Ready reports whether the process should receive traffic, with the
+// status and latency of each dependency.
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	report := h.checker.Report(c.UserContext())
+	return h.send(c, report, report)
+}
+
+// Version reports the build metadata of the running binary.
+func (h *HealthHandler) Version(c *fiber.Ctx) error {
+	return utils.SendSuccess(c, buildinfo.Get())
+}
+
+// Check is Ready with the build metadata and cache statistics added.
 func (h *HealthHandler) Check(c *fiber.Ctx) error {
+	report := h.checker.Report(c.UserContext())
+	info := buildinfo.Get()
 	data := map[string]interface{}{
-		"status":  "healthy",
-		"service": "github.com/faizalnurrozi/go-starter-kit",
-		"version": "1.0.0",
+		"status":     report.Status,
+		"service":    info.Service,
+		"version":    info.Version,
+		"commit":     info.Commit,
+		"build_time": info.BuildTime,
+		"go_version": info.GoVersion,
+		"checks":     report.Checks,
+	}
+
+	// Tiered caches report how often each layer answers
+	if stats, ok := cache.StatsOf(h.cache); ok {
+		data["cache"] = map[string]interface{}{
+			"l1_hits":     stats.L1Hits,
+			"l2_hits":     stats.L2Hits,
+			"misses":      stats.Misses,
+			"l1_hit_rate": stats.L1HitRate(),
+			"l2_hit_rate": stats.L2HitRate(),
+		}
 	}
 
+	return h.send(c, report, data)
+}
+
+func (h *HealthHandler) send(c *fiber.Ctx, report health.Report, data interface{}) error {
+	if !report.Ready() {
+		for name, result := range report.Checks {
+			if result.Status != health.StatusUp {
+				log.Warn(c.UserContext(), "Health check failed",
+					logger.String("check", name),
+					logger.String("error", result.Error),
+				)
+			}
+		}
+		return utils.SendUnavailable(c, "Service unavailable", data)
+	}
 	return utils.SendSuccess(c, data)
 }
@@ -0,0 +1,90 @@
+package handler
+
+import (
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+	"github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
+	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type MFAHandler struct {
+	mfaService interfaces.MFAService
+}
+
+func NewMFAHandler(mfaService interfaces.MFAService) *MFAHandler {
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+func (h *MFAHandler) Enroll(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return utils.SendError(c, errors.NewUnauthorizedError())
+	}
+
+	result, err := h.mfaService.EnrollTOTP(c.UserContext(), principal.UserID)
+	if err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, result)
+}
+
+func (h *MFAHandler) Confirm(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return utils.SendError(c, errors.NewUnauthorizedError())
+	}
+	req := c.Locals("validatedRequest").(*dto.TOTPCodeRequest)
+
+	result, err := h.mfaService.ConfirmTOTP(c.UserContext(), principal.UserID, req)
+	if err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, result)
+}
+
+func (h *MFAHandler) Disable(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return utils.SendError(c, errors.NewUnauthorizedError())
+	}
+	req := c.Locals("validatedRequest").(*dto.TOTPCodeRequest)
+
+	if err := h.mfaService.DisableTOTP(c.UserContext(), principal.UserID, req); err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, map[string]string{"message": "Two-factor authentication disabled"})
+}
+
+func (h *MFAHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return utils.SendError(c, errors.NewUnauthorizedError())
+	}
+	req := c.Locals("validatedRequest").(*dto.TOTPCodeRequest)
+
+	result, err := h.mfaService.RegenerateRecoveryCodes(c.UserContext(), principal.UserID, req)
+	if err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, result)
+}
+
+// Reset is the admin endpoint that clears another user's second factor.
+func (h *MFAHandler) Reset(c *fiber.Ctx) error {
+	params := c.Locals("validatedParams").(*dto.GetUserParams)
+
+	if err := h.mfaService.Reset(c.UserContext(), params.ID); err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, map[string]string{"message": "Two-factor authentication reset"})
+}
@@ -2,8 +2,11 @@ package handler
 
 import (
 	"strconv"
+	"time"
 
 	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
 	"github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
 	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
 
@@ -23,7 +26,7 @@ func NewUserHandler(userService interfaces.UserService) *UserHandler {
 func (h *UserHandler) Create(c *fiber.Ctx) error {
 	req := c.Locals("validatedRequest").(*dto.CreateUserRequest)
 
-	user, err := h.userService.Create(c.Context(), req)
+	user, err := h.userService.Create(c.UserContext(), req)
 	if err != nil {
 		return utils.SendError(c, err)
 	}
@@ -34,11 +37,12 @@ func (h *UserHandler) Create(c *fiber.Ctx) error {
 func (h *UserHandler) GetByID(c *fiber.Ctx) error {
 	params := c.Locals("validatedParams").(*dto.GetUserParams)
 
-	user, err := h.userService.GetByID(c.Context(), params.ID)
+	user, err := h.userService.GetByID(c.UserContext(), params.ID)
 	if err != nil {
 		return utils.SendError(c, err)
 	}
 
+	utils.SetLastModified(c, user.UpdatedAt)
 	return utils.SendSuccess(c, user)
 }
 
@@ -46,11 +50,19 @@ func (h *UserHandler) GetAll(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	offset, _ := strconv.Atoi(c.Query("offset", "0"))
 
-	users, err := h.userService.GetAll(c.Context(), limit, offset)
+	users, err := h.userService.GetAll(c.UserContext(), limit, offset)
 	if err != nil {
 		return utils.SendError(c, err)
 	}
 
+	var lastModified time.Time
+	for _, user := range users {
+		if user.UpdatedAt.After(lastModified) {
+			lastModified = user.UpdatedAt
+		}
+	}
+	utils.SetLastModified(c, lastModified)
+
 	return utils.SendSuccess(c, users)
 }
 
@@ -58,7 +70,7 @@ func (h *UserHandler) Update(c *fiber.Ctx) error {
 	params := c.Locals("validatedParams").(*dto.GetUserParams)
 	req := c.Locals("validatedRequest").(*dto.UpdateUserRequest)
 
-	user, err := h.userService.Update(c.Context(), params.ID, req)
+	user, err := h.userService.Update(c.UserContext(), params.ID, req)
 	if err != nil {
 		return utils.SendError(c, err)
 	}
@@ -69,7 +81,54 @@ func (h *UserHandler) Update(c *fiber.Ctx) error {
 func (h *UserHandler) Delete(c *fiber.Ctx) error {
 	params := c.Locals("validatedParams").(*dto.GetUserParams)
 
-	err := h.userService.Delete(c.Context(), params.ID)
+	err := h.userService.Delete(c.UserContext(), params.ID)
+	if err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, map[string]string{"message": "User deleted successfully"})
+}
+
+func (h *UserHandler) GetCurrent(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return utils.SendError(c, errors.NewUnauthorizedError())
+	}
+
+	user, err := h.userService.GetByID(c.UserContext(), principal.UserID)
+	if err != nil {
+		return utils.SendError(c, err)
+	}
+
+	utils.SetLastModified(c, user.UpdatedAt)
+	return utils.SendSuccess(c, user)
+}
+
+func (h *UserHandler) UpdateCurrent(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return utils.SendError(c, errors.NewUnauthorizedError())
+	}
+	req := c.Locals("validatedRequest").(*dto.UpdateCurrentUserRequest)
+
+	user, err := h.userService.Update(c.UserContext(), principal.UserID, &dto.UpdateUserRequest{
+		Name:  req.Name,
+		Email: req.Email,
+	})
+	if err != nil {
+		return utils.SendError(c, err)
+	}
+
+	return utils.SendSuccess(c, user)
+}
+
+func (h *UserHandler) DeleteCurrent(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return utils.SendError(c, errors.NewUnauthorizedError())
+	}
+
+	err := h.userService.Delete(c.UserContext(), principal.UserID)
 	if err != nil {
 		return utils.SendError(c, err)
 	}
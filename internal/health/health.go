@@ -0,0 +1,183 @@
+// Package health runs dependency checks for the readiness endpoints.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+	// StatusShuttingDown is reported once graceful shutdown has started, so
+	// load balancers stop routing new requests before connections drain.
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check reports whether a dependency is usable. It must honour ctx.
+type Check func(ctx context.Context) error
+
+// Result is the outcome of one check.
+type Result struct {
+	Status    string    `json:"status"`
+	Latency   float64   `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	Optional  bool      `json:"optional,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the outcome of all checks. Status is down if any required check
+// failed and degraded if only optional ones did.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Ready reports whether the process should receive traffic.
+func (r Report) Ready() bool {
+	return r.Status == StatusUp || r.Status == StatusDegraded
+}
+
+type check struct {
+	name     string
+	fn       Check
+	optional bool
+}
+
+// Checker runs registered checks concurrently, each with its own timeout,
+// and reuses results for a short while so frequent probes do not load the
+// dependencies.
+type Checker struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu       sync.Mutex
+	checks   []check
+	results  map[string]Result
+	expires  time.Time
+	stopping atomic.Bool
+}
+
+func NewChecker(cfg config.HealthConfig) *Checker {
+	return &Checker{
+		timeout:  time.Duration(cfg.Timeout) * time.Millisecond,
+		cacheTTL: time.Duration(cfg.CacheTTL) * time.Millisecond,
+		results:  map[string]Result{},
+	}
+}
+
+// Register adds a check whose failure makes the process not ready.
+func (c *Checker) Register(name string, fn Check) {
+	c.register(check{name: name, fn: fn})
+}
+
+// RegisterOptional adds a check whose failure only degrades the report, for
+// dependencies the process can run without.
+func (c *Checker) RegisterOptional(name string, fn Check) {
+	c.register(check{name: name, fn: fn, optional: true})
+}
+
+func (c *Checker) register(ch check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, ch)
+	c.expires = time.Time{}
+}
+
+// Shutdown makes every later report not ready.
+func (c *Checker) Shutdown() {
+	c.stopping.Store(true)
+}
+
+// Drain makes every later report not ready, then waits delay so probes and
+// load balancers notice before the servers stop. It returns early with
+// ctx's error once ctx is done.
+func (c *Checker) Drain(ctx context.Context, delay time.Duration) error {
+	c.Shutdown()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Report returns the latest results, running the checks again once the
+// cached ones have expired. Concurrent callers wait for a single run.
+func (c *Checker) Report(ctx context.Context) Report {
+	c.mu.Lock()
+	if time.Now().After(c.expires) {
+		// Results are shared, so one caller giving up must not fail them
+		c.results = c.run(context.WithoutCancel(ctx))
+		c.expires = time.Now().Add(c.cacheTTL)
+	}
+	results := make(map[string]Result, len(c.results))
+	for name, result := range c.results {
+		results[name] = result
+	}
+	c.mu.Unlock()
+
+	report := Report{Status: StatusUp, Checks: results}
+	for _, result := range results {
+		if result.Status == StatusUp {
+			continue
+		}
+		if !result.Optional {
+			report.Status = StatusDown
+			break
+		}
+		report.Status = StatusDegraded
+	}
+	if c.stopping.Load() {
+		report.Status = StatusShuttingDown
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context) map[string]Result {
+	results := make(map[string]Result, len(c.checks))
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, ch := range c.checks {
+		wg.Add(1)
+		go func(ch check) {
+			defer wg.Done()
+			result := c.runOne(ctx, ch)
+			mu.Lock()
+			results[ch.name] = result
+			mu.Unlock()
+		}(ch)
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *Checker) runOne(ctx context.Context, ch check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := ch.fn(ctx)
+	result := Result{
+		Status:    StatusUp,
+		Latency:   float64(time.Since(start).Microseconds()) / 1000,
+		Optional:  ch.optional,
+		CheckedAt: start.UTC(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+// Package lifecycle starts the parts of the process in order and stops them
+// in reverse under a shared deadline.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+)
+
+var log = logger.Named("lifecycle")
+
+// Hook is one component of the process. OnStart must return once the
+// component is ready, handing long-running work to Manager.Go. Either
+// function may be nil; resources opened before Run only need OnStop.
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Server listens synchronously so startup errors surface from Start, then
+// serves in the background until stopped.
+type Server interface {
+	Listen() error
+	Serve() error
+	Stop(ctx context.Context) error
+}
+
+// Manager runs hooks in the order they were appended and stops them in
+// reverse, so resources appended first (databases, caches) close last.
+type Manager struct {
+	timeout time.Duration
+	hooks   []Hook
+	errs    chan error
+}
+
+// New returns a Manager that gives all hooks timeout, together, to stop.
+func New(timeout time.Duration) *Manager {
+	return &Manager{timeout: timeout, errs: make(chan error, 1)}
+}
+
+func (m *Manager) Append(hook Hook) {
+	m.hooks = append(m.hooks, hook)
+}
+
+// AppendServer appends a hook that listens on start, serves in the
+// background and stops s on shutdown.
+func (m *Manager) AppendServer(name string, s Server) {
+	m.Append(Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			if err := s.Listen(); err != nil {
+				return err
+			}
+			m.Go(name, s.Serve)
+			return nil
+		},
+		OnStop: s.Stop,
+	})
+}
+
+// Go runs fn in the background. If it fails, Run stops the process.
+func (m *Manager) Go(name string, fn func() error) {
+	go func() {
+		if err := fn(); err != nil {
+			select {
+			case m.errs <- fmt.Errorf("%s: %w", name, err):
+			default:
+			}
+		}
+	}()
+}
+
+// Run starts every hook, waits until ctx is done or a background task
+// fails, then stops the started hooks. A hook that fails to start stops
+// the ones before it and its error is returned.
+func (m *Manager) Run(ctx context.Context) error {
+	var runErr error
+	started := 0
+	for _, hook := range m.hooks {
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				runErr = fmt.Errorf("start %s: %w", hook.Name, err)
+				break
+			}
+		}
+		started++
+	}
+
+	if runErr == nil {
+		select {
+		case <-ctx.Done():
+			logger.Info("Shutting down server...")
+		case runErr = <-m.errs:
+			log.Error(context.Background(), "Component failed, shutting down", logger.Err(runErr))
+		}
+	}
+
+	return errors.Join(runErr, m.stop(started))
+}
+
+func (m *Manager) stop(started int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	var errs []error
+	for i := started - 1; i >= 0; i-- {
+		hook := m.hooks[i]
+		if hook.OnStop == nil {
+			continue
+		}
+
+		start := time.Now()
+		if err := hook.OnStop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stop %s: %w", hook.Name, err))
+			continue
+		}
+		log.Debug(ctx, "Stopped", logger.String("component", hook.Name), logger.Duration("took", time.Since(start)))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+const redacted = "[REDACTED]"
+
+// formatter applies level overrides, sampling and redaction before handing
+// the entry to the configured output format. Dropped entries format to
+// nothing.
+type formatter struct {
+	next       logrus.Formatter
+	redactKeys []string
+	sampler    *sampler
+}
+
+func (f *formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	pkg, _ := entry.Data[packageKey].(string)
+	if !levels.enabled(pkg, entry.Level) {
+		return nil, nil
+	}
+	if f.sampler != nil && !f.sampler.allow(entry) {
+		return nil, nil
+	}
+
+	clean := *entry
+	clean.Data = make(logrus.Fields, len(entry.Data))
+	for key, value := range entry.Data {
+		if f.sensitive(key) {
+			value = redacted
+		}
+		clean.Data[key] = value
+	}
+	return f.next.Format(&clean)
+}
+
+func (f *formatter) sensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, k := range f.redactKeys {
+		if strings.Contains(key, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// sampler keeps the first Initial info and debug entries with the same
+// message in each interval, then every Thereafter-th. Warnings and errors
+// are never sampled.
+type sampler struct {
+	mu         sync.Mutex
+	initial    int
+	thereafter int
+	interval   time.Duration
+	reset      time.Time
+	counts     map[string]int
+}
+
+func newSampler(cfg config.LogSamplingConfig) *sampler {
+	return &sampler{
+		initial:    cfg.Initial,
+		thereafter: cfg.Thereafter,
+		interval:   time.Duration(cfg.Interval) * time.Second,
+		counts:     map[string]int{},
+	}
+}
+
+func (s *sampler) allow(entry *logrus.Entry) bool {
+	if entry.Level < logrus.InfoLevel {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := time.Now(); now.After(s.reset) {
+		s.counts = map[string]int{}
+		s.reset = now.Add(s.interval)
+	}
+
+	s.counts[entry.Message]++
+	n := s.counts[entry.Message]
+	if n <= s.initial {
+		return true
+	}
+	return s.thereafter > 0 && (n-s.initial)%s.thereafter == 0
+}
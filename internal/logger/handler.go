@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type levelRequest struct {
+	Package string `json:"package"`
+	Level   string `json:"level"`
+}
+
+type levelResponse struct {
+	Level    string            `json:"level"`
+	Packages map[string]string `json:"packages"`
+}
+
+// LevelHandler reports the current levels on GET and changes one on PUT,
+// with a body of {"level": "debug"} for the default or
+// {"package": "cache", "level": "debug"} for an override. An empty level
+// removes a package override.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req levelRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+
+			var err error
+			if req.Package == "" {
+				err = SetLevel(req.Level)
+			} else {
+				err = SetPackageLevel(req.Package, req.Level)
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			Info("Log level changed: ", req.Package, " ", req.Level)
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		def, packages := levels.snapshot()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(levelResponse{Level: def, Packages: packages})
+	})
+}
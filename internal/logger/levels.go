@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// levelState holds the default level and per-package overrides. logrus
+// itself is set to the most verbose of them; entries below their package's
+// level are dropped by the formatter.
+type levelState struct {
+	mu       sync.RWMutex
+	def      logrus.Level
+	packages map[string]logrus.Level
+}
+
+var levels = &levelState{def: logrus.InfoLevel, packages: map[string]logrus.Level{}}
+
+// configure replaces all levels. Unparseable default levels fall back to
+// info, as they always have; unparseable overrides are errors.
+func (s *levelState) configure(def string, packages map[string]string) error {
+	parsed := make(map[string]logrus.Level, len(packages))
+	for name, level := range packages {
+		l, err := logrus.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("log level for %s: %w", name, err)
+		}
+		parsed[name] = l
+	}
+
+	defLevel, err := logrus.ParseLevel(def)
+	if err != nil {
+		defLevel = logrus.InfoLevel
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.def = defLevel
+	s.packages = parsed
+	return nil
+}
+
+func (s *levelState) enabled(pkg string, level logrus.Level) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	threshold, ok := s.packages[pkg]
+	if !ok {
+		threshold = s.def
+	}
+	return level <= threshold
+}
+
+func (s *levelState) lowest() logrus.Level {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	lowest := s.def
+	for _, level := range s.packages {
+		if level > lowest {
+			lowest = level
+		}
+	}
+	return lowest
+}
+
+func (s *levelState) snapshot() (string, map[string]string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	packages := make(map[string]string, len(s.packages))
+	for name, level := range s.packages {
+		packages[name] = level.String()
+	}
+	return s.def.String(), packages
+}
+
+// SetLevel changes the default level at runtime.
+func SetLevel(level string) error {
+	l, err := logrus.ParseLevel(level)
+	if err != nil {
+		return err
+	}
+
+	levels.mu.Lock()
+	levels.def = l
+	levels.mu.Unlock()
+
+	log.SetLevel(levels.lowest())
+	return nil
+}
+
+// SetPackageLevel overrides the level of a Named logger at runtime. An empty
+// level removes the override.
+func SetPackageLevel(pkg, level string) error {
+	levels.mu.Lock()
+	if level == "" {
+		delete(levels.packages, pkg)
+	} else {
+		l, err := logrus.ParseLevel(level)
+		if err != nil {
+			levels.mu.Unlock()
+			return err
+		}
+		levels.packages[pkg] = l
+	}
+	levels.mu.Unlock()
+
+	log.SetLevel(levels.lowest())
+	return nil
+}
+
+// SetLevels replaces the default level and every package override, as
+// when log.level or log.levels are reloaded.
+func SetLevels(def string, packages map[string]string) error {
+	if _, err := logrus.ParseLevel(def); err != nil {
+		return err
+	}
+	if err := levels.configure(def, packages); err != nil {
+		return err
+	}
+
+	log.SetLevel(levels.lowest())
+	return nil
+}
@@ -1,30 +1,63 @@
 package logger
 
 import (
-    "os"
+    "context"
+    "fmt"
     "io"
-    
+    "os"
+
+    "github.com/faizalnurrozi/go-starter-kit/internal/auth"
+    "github.com/faizalnurrozi/go-starter-kit/internal/config"
+    "github.com/faizalnurrozi/go-starter-kit/internal/requestid"
+
     "github.com/sirupsen/logrus"
+    "go.opentelemetry.io/otel/trace"
 )
 
 var log *logrus.Logger
 
+// Init configures the logger at the given level with default settings.
+// "silent" discards all output, which tests use.
 func Init(level string) {
-    log = logrus.New()
+    if err := Setup(config.LogConfig{Level: level}); err != nil {
+        fmt.Fprintln(os.Stderr, err)
+    }
+}
+
+// Setup configures the global logger from cfg.
+func Setup(cfg config.LogConfig) error {
+    l := logrus.New()
 
-    if level == "silent" {
-        log.SetOutput(io.Discard)
+    if cfg.Level == "silent" {
+        l.SetOutput(io.Discard)
     } else {
-        log.SetOutput(os.Stdout)
+        l.SetOutput(os.Stdout)
     }
 
-    log.SetFormatter(&logrus.JSONFormatter{})
+    var next logrus.Formatter
+    switch cfg.Format {
+    case "json", "":
+        next = &logrus.JSONFormatter{}
+    case "text":
+        next = &logrus.TextFormatter{FullTimestamp: true}
+    default:
+        return fmt.Errorf("unsupported log format: %s", cfg.Format)
+    }
+
+    if err := levels.configure(cfg.Level, cfg.Levels); err != nil {
+        return err
+    }
+    l.SetLevel(levels.lowest())
 
-    logLevel, err := logrus.ParseLevel(level)
-    if err != nil {
-        logLevel = logrus.InfoLevel
+    var s *sampler
+    if cfg.Sampling.Enabled {
+        s = newSampler(cfg.Sampling)
     }
-    log.SetLevel(logLevel)
+    l.SetFormatter(&formatter{next: next, redactKeys: cfg.RedactKeys, sampler: s})
+    l.AddHook(traceHook{})
+
+    log = l
+    return nil
 }
 
 func SetOutput(w io.Writer) {
@@ -33,7 +66,8 @@ func SetOutput(w io.Writer) {
 	}
 }
 
-
+// Info, Error, Debug, Warn and Fatal log process-level messages that have no
+// request context, such as startup and shutdown. Use Named loggers elsewhere.
 func Info(args ...interface{}) {
     log.Info(args...)
 }
@@ -58,6 +92,47 @@ func WithFields(fields logrus.Fields) *logrus.Entry {
     return log.WithFields(fields)
 }
 
+// FromContext returns an entry carrying the request ID and authenticated
+// user found in ctx, plus trace and span IDs once logged.
+func FromContext(ctx context.Context) *logrus.Entry {
+    fields := logrus.Fields{}
+    if id := requestid.FromContext(ctx); id != "" {
+        fields["request_id"] = id
+    }
+    if principal, ok := auth.PrincipalFromContext(ctx); ok {
+        // Services log the user they act on as user_id
+        if principal.System != "" {
+            fields["actor_system"] = principal.System
+        } else {
+            fields["actor_id"] = principal.UserID
+        }
+    }
+    return log.WithContext(ctx).WithFields(fields)
+}
+
 func GetLogger() *logrus.Logger {
     return log
 }
+
+// traceHook adds the trace and span IDs found in an entry's context, so
+// lines logged through FromContext can be matched to traces.
+type traceHook struct{}
+
+func (traceHook) Levels() []logrus.Level {
+    return logrus.AllLevels
+}
+
+func (traceHook) Fire(entry *logrus.Entry) error {
+    if entry.Context == nil {
+        return nil
+    }
+
+    spanContext := trace.SpanContextFromContext(entry.Context)
+    if !spanContext.IsValid() {
+        return nil
+    }
+
+    entry.Data["trace_id"] = spanContext.TraceID().String()
+    entry.Data["span_id"] = spanContext.SpanID().String()
+    return nil
+}
@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// packageKey is the field naming the Named logger an entry came from. It
+// selects the entry's level override.
+const packageKey = "logger"
+
+// Field is a typed key/value pair attached to a log entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, value string) Field      { return Field{key, value} }
+func Int(key string, value int) Field     { return Field{key, value} }
+func Int64(key string, value int64) Field { return Field{key, value} }
+func Uint(key string, value uint) Field   { return Field{key, value} }
+func Bool(key string, value bool) Field   { return Field{key, value} }
+func Duration(key string, value time.Duration) Field {
+	return Field{key, value.String()}
+}
+func Any(key string, value interface{}) Field { return Field{key, value} }
+
+// Err attaches err under the "error" key.
+func Err(err error) Field {
+	if err == nil {
+		return Field{"error", nil}
+	}
+	return Field{"error", err.Error()}
+}
+
+// Logger writes entries for one package, whose level can be overridden in
+// log.levels or at runtime.
+type Logger struct {
+	name string
+}
+
+// Named returns the logger for a package. Loggers are cheap; keep one in a
+// package-level variable.
+func Named(name string) *Logger {
+	return &Logger{name: name}
+}
+
+func (l *Logger) Debug(ctx context.Context, msg string, fields ...Field) {
+	l.log(ctx, logrus.DebugLevel, msg, fields)
+}
+
+func (l *Logger) Info(ctx context.Context, msg string, fields ...Field) {
+	l.log(ctx, logrus.InfoLevel, msg, fields)
+}
+
+func (l *Logger) Warn(ctx context.Context, msg string, fields ...Field) {
+	l.log(ctx, logrus.WarnLevel, msg, fields)
+}
+
+func (l *Logger) Error(ctx context.Context, msg string, fields ...Field) {
+	l.log(ctx, logrus.ErrorLevel, msg, fields)
+}
+
+func (l *Logger) log(ctx context.Context, level logrus.Level, msg string, fields []Field) {
+	if !levels.enabled(l.name, level) {
+		return
+	}
+
+	data := make(logrus.Fields, len(fields)+1)
+	data[packageKey] = l.name
+	for _, f := range fields {
+		data[f.Key] = f.Value
+	}
+	FromContext(ctx).WithFields(data).Log(level, msg)
+}
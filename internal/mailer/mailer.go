@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers transactional email. Implementations must be safe for
+// concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the Mailer selected by cfg.Driver.
+func New(cfg config.MailConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case "smtp":
+		return NewSMTPMailer(cfg), nil
+	case "file":
+		return NewFileMailer(cfg.From, cfg.FileDir), nil
+	case "log", "":
+		return NewLogMailer(cfg.From), nil
+	default:
+		return nil, fmt.Errorf("unsupported mail driver: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+)
+
+var log = logger.Named("mailer")
+
+// logMailer writes messages to the application log instead of sending them.
+// It is the default for local development.
+type logMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) Mailer {
+	return &logMailer{from: from}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg Message) error {
+	log.Info(ctx, "Mail not sent (log mailer)",
+		logger.String("from", m.from),
+		logger.String("to", msg.To),
+		logger.String("subject", msg.Subject),
+		logger.String("body", msg.Body),
+	)
+	return nil
+}
+
+// fileMailer writes each message as an .eml file that can be opened in a mail
+// client, which is handy for checking rendered emails without an SMTP server.
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func NewFileMailer(from, dir string) Mailer {
+	return &fileMailer{from: from, dir: dir}
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg Message) error {
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	return os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, msg), 0o644)
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"github.com/faizalnurrozi/go-starter-kit/internal/buildinfo"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// newBuildInfo is a constant 1 labelled with the binary's build metadata,
+// so dashboards can join on it or spot mixed versions during a rollout.
+func newBuildInfo() prometheus.Collector {
+	info := buildinfo.Get()
+	return prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "app_build_info",
+		Help: "Build metadata of the running binary; always 1.",
+		ConstLabels: prometheus.Labels{
+			"service":    info.Service,
+			"version":    info.Version,
+			"commit":     info.Commit,
+			"build_time": info.BuildTime,
+			"go_version": info.GoVersion,
+		},
+	}, func() float64 { return 1 })
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterCacheStats exports the per-tier hit counters of a tiered cache.
+func RegisterCacheStats(stats func() cache.Stats) error {
+	counter := func(tier string, value func(cache.Stats) uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name:        "cache_tier_lookups_total",
+			Help:        "Tiered cache lookups by the tier that answered.",
+			ConstLabels: prometheus.Labels{"tier": tier},
+		}, func() float64 {
+			return float64(value(stats()))
+		})
+	}
+
+	for _, c := range []prometheus.Collector{
+		counter("l1", func(s cache.Stats) uint64 { return s.L1Hits }),
+		counter("l2", func(s cache.Stats) uint64 { return s.L2Hits }),
+		counter("miss", func(s cache.Stats) uint64 { return s.Misses }),
+	} {
+		if err := Registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package metrics holds the Prometheus collectors exported on the admin
+// port. Collectors are package-level and registered on Registry.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every collector in this package plus Go runtime and
+// process metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests by route template, method and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency by route template, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	GRPCRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_server_handled_total",
+		Help: "gRPC calls completed by method and status code.",
+	}, []string{"method", "code"})
+
+	GRPCDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "grpc_server_handling_seconds",
+		Help:    "gRPC call latency by method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "GORM statement latency by operation and table.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"operation", "table"})
+
+	CacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_lookups_total",
+		Help: "Read-through cache lookups by cache and result.",
+	}, []string{"cache", "result"})
+
+	RateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limited_requests_total",
+		Help: "Requests rejected by rate limiting, by transport and rule.",
+	}, []string{"transport", "rule"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		newBuildInfo(),
+		HTTPRequests,
+		HTTPDuration,
+		GRPCRequests,
+		GRPCDuration,
+		DBQueryDuration,
+		CacheLookups,
+		RateLimited,
+	)
+}
+
+// RegisterDBStats exports the connection pool stats of db under name.
+func RegisterDBStats(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves Registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
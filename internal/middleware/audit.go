@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/requestid"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditMetadata records the request details stored on audit events.
+func AuditMetadata() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.SetUserContext(audit.WithMetadata(c.UserContext(), &audit.Metadata{
+			RequestID: requestid.FromContext(c.UserContext()),
+			IP:        c.IP(),
+			UserAgent: c.Get(fiber.HeaderUserAgent),
+		}))
+		return c.Next()
+	}
+}
@@ -1,18 +1,58 @@
 package middleware
 
 import (
-	"fmt"
+	"context"
 	"strings"
 
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
 	"github.com/faizalnurrozi/go-starter-kit/internal/config"
 	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
 	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt/v5"
 )
 
-func Auth() fiber.Handler {
+// Auth accepts access tokens only.
+func Auth(cfg config.JWTConfig) fiber.Handler {
+	return authenticate(cfg.Secret.Value(), auth.PurposeAccess)
+}
+
+// MFAEnrollmentAuth additionally accepts the enrollment token handed out at
+// login to users whose role requires a second factor they have not set up.
+func MFAEnrollmentAuth(cfg config.JWTConfig) fiber.Handler {
+	return authenticate(cfg.Secret.Value(), auth.PurposeAccess, auth.PurposeMFAEnroll)
+}
+
+// Authorizer checks a user's current account against roles, as
+// AuthService.Authorize does.
+type Authorizer interface {
+	Authorize(ctx context.Context, userID uint, roles ...string) error
+}
+
+// RequireRole must run after Auth and rejects principals whose account does
+// not currently have one of roles. The role in the token is not trusted, so
+// a demoted user loses access before their token expires.
+func RequireRole(authz Authorizer, roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			return utils.SendError(c, errors.NewUnauthorizedError())
+		}
+		if err := authz.Authorize(c.UserContext(), principal.UserID, roles...); err != nil {
+			return utils.SendError(c, err)
+		}
+		return c.Next()
+	}
+}
+
+// CurrentPrincipal returns the caller authenticated by Auth or
+// MFAEnrollmentAuth. It is false on routes without authentication.
+func CurrentPrincipal(c *fiber.Ctx) (*auth.Principal, bool) {
+	return auth.PrincipalFromContext(c.UserContext())
+}
+
+func authenticate(secret string, purposes ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -24,21 +64,27 @@ func Auth() fiber.Handler {
 			return utils.SendError(c, errors.NewUnauthorizedError())
 		}
 
-		cfg := config.Get()
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(cfg.JWT.Secret), nil
-		})
-
-		if err != nil || !token.Valid {
-			fmt.Println(err)
+		claims, err := auth.ParseToken(secret, tokenString)
+		if err != nil {
+			log.Debug(c.UserContext(), "Rejected token", logger.Err(err))
 			return utils.SendError(c, errors.NewUnauthorizedError())
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok {
-			c.Locals("user_id", claims["user_id"])
-			c.Locals("email", claims["email"])
+		if !hasPurpose(claims.Purpose, purposes) {
+			return utils.SendError(c, errors.NewUnauthorizedError())
 		}
 
+		c.SetUserContext(auth.WithPrincipal(c.UserContext(), auth.NewPrincipal(claims)))
+
 		return c.Next()
 	}
 }
+
+func hasPurpose(purpose string, allowed []string) bool {
+	for _, p := range allowed {
+		if p == purpose {
+			return true
+		}
+	}
+	return false
+}
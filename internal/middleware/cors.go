@@ -1,18 +1,69 @@
 package middleware
 
 import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
 	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-func CORS() fiber.Handler {
+// CORSOrigins matches request origins against cors.allow_origins. Set
+// replaces the patterns while serving.
+type CORSOrigins struct {
+	patterns atomic.Pointer[[]string]
+}
+
+func NewCORSOrigins(patterns []string) *CORSOrigins {
+	o := &CORSOrigins{}
+	o.Set(patterns)
+	return o
+}
+
+// Set replaces the allowed origin patterns, as when cors is reloaded.
+func (o *CORSOrigins) Set(patterns []string) {
+	normalized := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		normalized = append(normalized, strings.TrimSuffix(strings.ToLower(strings.TrimSpace(p)), "/"))
+	}
+	o.patterns.Store(&normalized)
+}
+
+// Allow reports whether origin matches a pattern: "*" matches any origin,
+// "https://*.example.com" any subdomain of example.com but not example.com
+// itself, and anything else only the same origin.
+func (o *CORSOrigins) Allow(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, p := range *o.patterns.Load() {
+		if p == "*" || p == origin {
+			return true
+		}
+
+		scheme, host, ok := strings.Cut(p, "://*.")
+		if !ok {
+			continue
+		}
+		rest, found := strings.CutPrefix(origin, scheme+"://")
+		if found && strings.HasSuffix(rest, "."+host) && len(rest) > len(host)+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// CORS applies the cross-origin policy in cfg, allowing the origins matched
+// by origins so they can change without rebuilding the app.
+func CORS(cfg config.CORSConfig, origins *CORSOrigins) fiber.Handler {
 	return cors.New(cors.Config{
-		AllowOrigins:     "*",
-		AllowMethods:     "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
-		AllowCredentials: true,
+		AllowOriginsFunc: origins.Allow,
+		AllowMethods:     strings.Join(cfg.AllowMethods, ","),
+		AllowHeaders:     strings.Join(cfg.AllowHeaders, ","),
+		ExposeHeaders:    strings.Join(cfg.ExposeHeaders, ","),
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
 	})
 }
 
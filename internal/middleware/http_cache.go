@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HTTPCache adds validators to successful GET responses: a weak ETag over
+// the body and the given Cache-Control. Handlers may set Last-Modified
+// themselves. Requests whose If-None-Match or If-Modified-Since still match
+// get 304 Not Modified without a body.
+func HTTPCache(cacheControl string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			return err
+		}
+		if c.Method() != fiber.MethodGet || c.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		etag := weakETag(c.Response().Body())
+		c.Set(fiber.HeaderETag, etag)
+		c.Vary(fiber.HeaderAccept)
+		if cacheControl != "" {
+			c.Set(fiber.HeaderCacheControl, cacheControl)
+		}
+
+		if notModified(c, etag) {
+			c.Status(fiber.StatusNotModified)
+			c.Response().ResetBody()
+		}
+		return nil
+	}
+}
+
+func weakETag(body []byte) string {
+	h := fnv.New64a()
+	h.Write(body)
+	return fmt.Sprintf(`W/"%x"`, h.Sum64())
+}
+
+// notModified applies RFC 9110 precedence: If-None-Match wins over
+// If-Modified-Since, and ETags are compared weakly.
+func notModified(c *fiber.Ctx, etag string) bool {
+	if match := c.Get(fiber.HeaderIfNoneMatch); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(c.Get(fiber.HeaderIfModifiedSince))
+	if err != nil {
+		return false
+	}
+	modified, err := http.ParseTime(string(c.Response().Header.Peek(fiber.HeaderLastModified)))
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
@@ -6,9 +6,10 @@ import (
 	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
 )
 
+var log = logger.Named("http")
+
 func Logger() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		start := time.Now()
@@ -17,14 +18,14 @@ func Logger() fiber.Handler {
 
 		latency := time.Since(start)
 
-		logger.WithFields(logrus.Fields{
-			"method":     c.Method(),
-			"path":       c.Path(),
-			"status":     c.Response().StatusCode(),
-			"latency":    latency.String(),
-			"ip":         c.IP(),
-			"user_agent": c.Get("User-Agent"),
-		}).Info("HTTP Request")
+		log.Info(c.UserContext(), "HTTP Request",
+			logger.String("method", c.Method()),
+			logger.String("path", c.Path()),
+			logger.Int("status", c.Response().StatusCode()),
+			logger.Duration("latency", latency),
+			logger.String("ip", c.IP()),
+			logger.String("user_agent", c.Get("User-Agent")),
+		)
 
 		return err
 	}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/metrics"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Metrics records request counts and latency labelled by the matched route
+// template, so /users/1 and /users/2 share a series.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+
+		// Requests that match no route would otherwise report this
+		// middleware's own path
+		route := c.Route().Path
+		if status == fiber.StatusNotFound {
+			route = "unmatched"
+		}
+
+		labels := []string{c.Method(), route, strconv.Itoa(status)}
+		metrics.HTTPRequests.WithLabelValues(labels...).Inc()
+		metrics.HTTPDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
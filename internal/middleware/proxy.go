@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TrustedProxies returns fc set up so c.IP() is the client address that
+// server.trusted_proxies forward in server.proxy_header, and the address of
+// the connection for requests from anywhere else. Without a proxy header fc
+// is returned unchanged.
+func TrustedProxies(fc fiber.Config, cfg config.ServerConfig) fiber.Config {
+	if cfg.ProxyHeader == "" {
+		return fc
+	}
+	fc.ProxyHeader = cfg.ProxyHeader
+	fc.EnableTrustedProxyCheck = true
+	fc.TrustedProxies = cfg.TrustedProxies
+	fc.EnableIPValidation = true
+	return fc
+}
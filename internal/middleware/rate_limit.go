@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/ratelimit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RateLimit rejects requests over the limit with 429 and Retry-After, and
+// adds RateLimit-* headers to every limited response. Register it after
+// Auth on authenticated routes so clients can be keyed by user.
+func RateLimit(limiter *ratelimit.Limiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		client := ratelimit.Client{
+			APIKey: c.Get(limiter.APIKeyHeader()),
+			IP:     c.IP(),
+		}
+		if principal, ok := CurrentPrincipal(c); ok {
+			client.UserID = principal.UserID
+		}
+
+		result, limited := limiter.TakeHTTP(c.UserContext(), c.Method(), c.Path(), client)
+		if !limited {
+			return c.Next()
+		}
+
+		for name, value := range result.Headers() {
+			c.Set(name, value)
+		}
+		if !result.Allowed {
+			return utils.SendError(c, errors.NewTooManyRequestsError())
+		}
+		return c.Next()
+	}
+}
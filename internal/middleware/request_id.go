@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"github.com/faizalnurrozi/go-starter-kit/internal/requestid"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestID accepts the caller's X-Request-ID or generates one, stores it in
+// c.UserContext() and echoes it on the response. Register it before any
+// middleware that logs or records audit metadata.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := requestid.Resolve(c.Get(requestid.Header))
+
+		c.Set(requestid.Header, id)
+		c.SetUserContext(requestid.WithID(c.UserContext(), id))
+
+		return c.Next()
+	}
+}
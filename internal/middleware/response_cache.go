@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type cachedResponse struct {
+	ContentType  string `json:"content_type"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// ResponseCache serves successful GET responses from c, keyed by path,
+// normalised query and response format. Entries live in the named cache
+// group, so invalidating the group drops them all. The cached body is shared
+// by every caller; only use it on routes whose response does not depend on
+// who is asking.
+func ResponseCache(c cache.Cache, group string, ttl time.Duration) fiber.Handler {
+	responses := cache.NewGroup(c, group)
+
+	return func(ctx *fiber.Ctx) error {
+		if ctx.Method() != fiber.MethodGet {
+			return ctx.Next()
+		}
+
+		key, err := responses.Key(ctx.UserContext(), responseCacheKey(ctx))
+		if err != nil {
+			log.Warn(ctx.UserContext(), "Error reading response cache generation", logger.Err(err))
+			return ctx.Next()
+		}
+
+		if data, err := c.Get(ctx.UserContext(), key); err == nil {
+			var cached cachedResponse
+			if err := json.Unmarshal(data, &cached); err == nil {
+				ctx.Set(fiber.HeaderContentType, cached.ContentType)
+				if cached.LastModified != "" {
+					ctx.Set(fiber.HeaderLastModified, cached.LastModified)
+				}
+				ctx.Set("X-Cache", "HIT")
+				return ctx.Status(fiber.StatusOK).Send(cached.Body)
+			}
+		}
+
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+		if ctx.Response().StatusCode() != fiber.StatusOK {
+			return nil
+		}
+
+		data, err := json.Marshal(cachedResponse{
+			ContentType:  string(ctx.Response().Header.ContentType()),
+			LastModified: string(ctx.Response().Header.Peek(fiber.HeaderLastModified)),
+			Body:         ctx.Response().Body(),
+		})
+		if err == nil {
+			err = c.Set(ctx.UserContext(), key, data, ttl)
+		}
+		if err != nil {
+			log.Warn(ctx.UserContext(), "Error caching response", logger.Err(err))
+		}
+		ctx.Set("X-Cache", "MISS")
+		return nil
+	}
+}
+
+// responseCacheKey identifies a response regardless of query parameter
+// order. The Accept header is included because it selects JSON or XML.
+func responseCacheKey(c *fiber.Ctx) string {
+	query := url.Values{}
+	c.Request().URI().QueryArgs().VisitAll(func(key, value []byte) {
+		query.Add(string(key), string(value))
+	})
+	for _, values := range query {
+		sort.Strings(values)
+	}
+
+	return c.Path() + "?" + query.Encode() + "|" + c.Get(fiber.HeaderAccept)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"github.com/faizalnurrozi/go-starter-kit/internal/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span for each request, continuing the trace from
+// an incoming traceparent header. The span is stored in c.UserContext(),
+// which handlers pass on to services.
+func Tracing() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := otel.GetTextMapPropagator().Extract(c.UserContext(), tracing.HeaderCarrier{Header: &c.Request().Header})
+		ctx, span := tracing.Tracer().Start(ctx, c.Method(),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Method()),
+				semconv.URLPath(c.Path()),
+				semconv.ClientAddress(c.IP()),
+			),
+		)
+		defer span.End()
+
+		c.SetUserContext(ctx)
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		route := c.Route().Path
+		span.SetName(c.Method() + " " + route)
+		span.SetAttributes(
+			semconv.HTTPRoute(route),
+			semconv.HTTPResponseStatusCode(status),
+		)
+		if status >= fiber.StatusInternalServerError || err != nil {
+			span.SetStatus(codes.Error, "")
+		}
+
+		return err
+	}
+}
@@ -43,6 +43,34 @@ func ValidateRequest(requestType interface{}) fiber.Handler {
 	}
 }
 
+func ValidateQuery(queryType interface{}) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Create new instance of query type
+		qType := reflect.TypeOf(queryType)
+		if qType.Kind() == reflect.Ptr {
+			qType = qType.Elem()
+		}
+		query := reflect.New(qType).Interface()
+
+		// Parse query string
+		if err := c.QueryParser(query); err != nil {
+			return utils.SendError(c, errors.NewValidationError("Invalid query parameters"))
+		}
+
+		// Validate
+		if err := validate.Struct(query); err != nil {
+			var errorMessages []string
+			for _, err := range err.(validator.ValidationErrors) {
+				errorMessages = append(errorMessages, err.Field()+" "+err.Tag())
+			}
+			return utils.SendError(c, errors.NewValidationError("Validation failed: "+strings.Join(errorMessages, ", ")))
+		}
+
+		c.Locals("validatedQuery", query)
+		return c.Next()
+	}
+}
+
 func ValidateParams() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		params := &dto.GetUserParams{}
@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often idle counters are dropped from memory.
+const sweepInterval = time.Minute
+
+type memoryStore struct {
+	mu        sync.Mutex
+	counters  map[string]*counter
+	lastSweep time.Time
+}
+
+type counter struct {
+	window    time.Duration
+	index     int64
+	prev, cur int64
+}
+
+// NewMemoryStore returns a Store counting in process memory, so each
+// instance enforces its limits separately.
+func NewMemoryStore() Store {
+	return &memoryStore{counters: make(map[string]*counter)}
+}
+
+func (s *memoryStore) Take(ctx context.Context, key string, rule Rule, now time.Time) (Result, error) {
+	index, elapsed := windowOf(rule, now)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	c, ok := s.counters[key]
+	if !ok || c.window != rule.Window {
+		c = &counter{window: rule.Window, index: index}
+		s.counters[key] = c
+	}
+	c.advance(index)
+
+	allowed := allows(rule, elapsed, c.prev, c.cur)
+	if allowed {
+		c.cur++
+	}
+	return newResult(rule, elapsed, c.prev, c.cur, allowed), nil
+}
+
+func (c *counter) advance(index int64) {
+	switch index {
+	case c.index:
+	case c.index + 1:
+		c.prev, c.cur = c.cur, 0
+	default:
+		c.prev, c.cur = 0, 0
+	}
+	c.index = index
+}
+
+// sweep drops counters that no longer affect any decision.
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, c := range s.counters {
+		index, _ := windowOf(Rule{Window: c.window}, now)
+		if index > c.index+1 {
+			delete(s.counters, key)
+		}
+	}
+}
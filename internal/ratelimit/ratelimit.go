@@ -0,0 +1,296 @@
+// Package ratelimit limits how often each client may call the API, with
+// counters kept in Redis or in process memory.
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/metrics"
+)
+
+var log = logger.Named("ratelimit")
+
+// keyPrefix namespaces the counters in Redis.
+const keyPrefix = "ratelimit"
+
+// Rule allows Requests per Window.
+type Rule struct {
+	Requests int
+	Window   time.Duration
+}
+
+// Result is the decision on one request, with what the client needs to
+// pace itself.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Window    time.Duration
+	// Reset is the time left in the current window.
+	Reset time.Duration
+	// RetryAfter is how long a rejected client should wait.
+	RetryAfter time.Duration
+}
+
+// Headers returns the RateLimit-* headers describing r, plus Retry-After
+// when the request was rejected. Durations are whole seconds, rounded up.
+func (r Result) Headers() map[string]string {
+	headers := map[string]string{
+		"RateLimit-Limit":     strconv.Itoa(r.Limit),
+		"RateLimit-Remaining": strconv.Itoa(r.Remaining),
+		"RateLimit-Reset":     strconv.Itoa(seconds(r.Reset)),
+		"RateLimit-Policy":    fmt.Sprintf("%d;w=%d", r.Limit, seconds(r.Window)),
+	}
+	if !r.Allowed {
+		headers["Retry-After"] = strconv.Itoa(max(seconds(r.RetryAfter), 1))
+	}
+	return headers
+}
+
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// Store counts requests. Take counts a request against key only if rule
+// allows it.
+type Store interface {
+	Take(ctx context.Context, key string, rule Rule, now time.Time) (Result, error)
+}
+
+// Client identifies a caller by each means the transport can see. Empty
+// fields are unknown.
+type Client struct {
+	APIKey string
+	UserID uint
+	IP     string
+}
+
+// Limiter applies the configured rules. Update replaces them while
+// serving; the store is fixed at construction.
+type Limiter struct {
+	store  Store
+	policy atomic.Pointer[policy]
+	now    func() time.Time
+}
+
+// New returns a Limiter counting in the Redis behind appCache, or in memory
+// when rate_limit.driver is memory. A disabled limiter without Redis falls
+// back to memory, so enabling it on reload still works on one instance.
+func New(cfg config.RateLimitConfig, appCache cache.Cache) (*Limiter, error) {
+	store := NewMemoryStore()
+	if cfg.Driver == "redis" {
+		client, ok := cache.RedisClientOf(appCache)
+		switch {
+		case ok:
+			store = NewRedisStore(client, keyPrefix)
+		case cfg.Enabled:
+			return nil, fmt.Errorf("rate_limit.driver redis needs cache.driver redis or tiered")
+		}
+	}
+	return NewLimiter(store, cfg)
+}
+
+// NewLimiter returns a Limiter counting in store.
+func NewLimiter(store Store, cfg config.RateLimitConfig) (*Limiter, error) {
+	l := &Limiter{store: store, now: time.Now}
+	if err := l.Update(cfg); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Update replaces the rules, as when rate_limit is reloaded.
+func (l *Limiter) Update(cfg config.RateLimitConfig) error {
+	p, err := newPolicy(cfg)
+	if err != nil {
+		return err
+	}
+	l.policy.Store(p)
+	return nil
+}
+
+// APIKeyHeader is the header, or gRPC metadata key, carrying API keys.
+func (l *Limiter) APIKeyHeader() string {
+	return l.policy.Load().cfg.APIKeyHeader
+}
+
+// TakeHTTP counts a request to method and path from client. It reports
+// false when no rule applies, in which case the request may proceed and
+// carries no rate limit headers.
+func (l *Limiter) TakeHTTP(ctx context.Context, method, path string, client Client) (Result, bool) {
+	p := l.policy.Load()
+	scope, rule, ok := p.match(p.routes, method, path)
+	return l.take(ctx, p, "http", scope, rule, ok, client)
+}
+
+// TakeGRPC counts a call to fullMethod from client, like TakeHTTP.
+func (l *Limiter) TakeGRPC(ctx context.Context, fullMethod string, client Client) (Result, bool) {
+	p := l.policy.Load()
+	scope, rule, ok := p.match(p.methods, "", fullMethod)
+	return l.take(ctx, p, "grpc", scope, rule, ok, client)
+}
+
+func (l *Limiter) take(ctx context.Context, p *policy, transport, scope string, rule Rule, ok bool, client Client) (Result, bool) {
+	if !p.cfg.Enabled || !ok {
+		return Result{}, false
+	}
+
+	now := l.now()
+	var result Result
+	for i, identity := range p.identities(client) {
+		r, err := l.store.Take(ctx, scope+":"+identity, rule, now)
+		if err != nil {
+			log.Warn(ctx, "Error counting request", logger.String("scope", scope), logger.Err(err))
+			if p.cfg.FailOpen {
+				return Result{}, false
+			}
+			r = Result{Limit: rule.Requests, Window: rule.Window, Reset: rule.Window, RetryAfter: time.Second}
+		}
+		if i == 0 || stricter(r, result) {
+			result = r
+		}
+		if !result.Allowed {
+			break
+		}
+	}
+
+	if !result.Allowed {
+		metrics.RateLimited.WithLabelValues(transport, scope).Inc()
+	}
+	return result, true
+}
+
+// policy is a parsed RateLimitConfig.
+type policy struct {
+	cfg     config.RateLimitConfig
+	def     *Rule
+	routes  []pattern
+	methods []pattern
+}
+
+// pattern matches "METHOD /path", "/path" or a gRPC method; a trailing *
+// matches any suffix. A nil rule exempts matching requests.
+type pattern struct {
+	name   string
+	method string
+	path   string
+	prefix bool
+	rule   *Rule
+}
+
+func newPolicy(cfg config.RateLimitConfig) (*policy, error) {
+	p := &policy{cfg: cfg}
+
+	var err error
+	if p.def, err = parseRule(cfg.Default); err != nil {
+		return nil, fmt.Errorf("rate_limit.default: %w", err)
+	}
+	if p.routes, err = parsePatterns(cfg.Routes, true); err != nil {
+		return nil, fmt.Errorf("rate_limit.routes: %w", err)
+	}
+	if p.methods, err = parsePatterns(cfg.Methods, false); err != nil {
+		return nil, fmt.Errorf("rate_limit.methods: %w", err)
+	}
+	return p, nil
+}
+
+func parseRule(s string) (*Rule, error) {
+	requests, window, err := config.ParseRateLimit(s)
+	if err != nil || requests == 0 {
+		return nil, err
+	}
+	return &Rule{Requests: requests, Window: window}, nil
+}
+
+func parsePatterns(entries []string, withMethod bool) ([]pattern, error) {
+	patterns := make([]pattern, 0, len(entries))
+	for _, entry := range entries {
+		name, requests, window, err := config.ParseRateLimitEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+
+		p := pattern{name: name, path: name}
+		if requests > 0 {
+			p.rule = &Rule{Requests: requests, Window: window}
+		}
+		if method, path, ok := strings.Cut(name, " "); withMethod && ok {
+			p.method, p.path = strings.ToUpper(method), strings.TrimSpace(path)
+		}
+		if strings.HasSuffix(p.path, "*") {
+			p.prefix, p.path = true, strings.TrimSuffix(p.path, "*")
+		}
+		patterns = append(patterns, p)
+	}
+	return patterns, nil
+}
+
+// match returns the first pattern matching the request, or the default
+// rule. Requests under the default rule share one counter per client.
+func (p *policy) match(patterns []pattern, method, path string) (string, Rule, bool) {
+	for _, pt := range patterns {
+		if pt.method != "" && pt.method != method {
+			continue
+		}
+		if path != pt.path && !(pt.prefix && strings.HasPrefix(path, pt.path)) {
+			continue
+		}
+		if pt.rule == nil {
+			return "", Rule{}, false
+		}
+		return pt.name, *pt.rule, true
+	}
+
+	if p.def == nil {
+		return "", Rule{}, false
+	}
+	return "default", *p.def, true
+}
+
+// stricter reports whether a leaves the client less room than b.
+func stricter(a, b Result) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	return a.Remaining < b.Remaining
+}
+
+// identities returns the counters a request from c is charged to: the
+// first of rate_limit.key_by the client can be known by and, until the
+// client has authenticated, its IP as well. API keys are not verified, so
+// inventing a new one for each request must not escape the IP limit.
+func (p *policy) identities(c Client) []string {
+	id := p.identity(c)
+	if c.UserID != 0 || strings.HasPrefix(id, "ip:") {
+		return []string{id}
+	}
+	return []string{id, "ip:" + c.IP}
+}
+
+// identity picks the first of rate_limit.key_by the client can be known
+// by. API keys are hashed so they are never stored.
+func (p *policy) identity(c Client) string {
+	for _, by := range p.cfg.KeyBy {
+		switch {
+		case by == "api_key" && c.APIKey != "":
+			sum := sha256.Sum256([]byte(c.APIKey))
+			return "key:" + hex.EncodeToString(sum[:16])
+		case by == "user" && c.UserID != 0:
+			return "user:" + strconv.FormatUint(uint64(c.UserID), 10)
+		case by == "ip" && c.IP != "":
+			return "ip:" + c.IP
+		}
+	}
+	return "ip:" + c.IP
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// takeScript checks and counts a request atomically, with the same
+// arithmetic as allows. KEYS are the current and previous window's
+// counters; ARGV the limit, the window and the time elapsed in it, in
+// milliseconds.
+var takeScript = redis.NewScript(`
+local cur = tonumber(redis.call("GET", KEYS[1]) or "0")
+local prev = tonumber(redis.call("GET", KEYS[2]) or "0")
+local limit, w, e = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+if prev * (w - e) + (cur + 1) * w > limit * w then
+	return {0, prev, cur}
+end
+cur = redis.call("INCR", KEYS[1])
+if cur == 1 then
+	redis.call("PEXPIRE", KEYS[1], 2 * w)
+end
+return {1, prev, cur}
+`)
+
+type redisStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisStore returns a Store whose counters live in Redis under prefix,
+// so every instance shares the same limits.
+func NewRedisStore(client redis.UniversalClient, prefix string) Store {
+	return &redisStore{client: client, prefix: prefix}
+}
+
+func (s *redisStore) Take(ctx context.Context, key string, rule Rule, now time.Time) (Result, error) {
+	index, elapsed := windowOf(rule, now)
+
+	// The hash tag keeps both windows of a key in one cluster slot
+	keys := []string{
+		fmt.Sprintf("%s:{%s}:%d", s.prefix, key, index),
+		fmt.Sprintf("%s:{%s}:%d", s.prefix, key, index-1),
+	}
+	values, err := takeScript.Run(ctx, s.client, keys, rule.Requests, rule.Window.Milliseconds(), elapsed).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	return newResult(rule, elapsed, values[1], values[2], values[0] == 1), nil
+}
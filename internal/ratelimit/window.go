@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"time"
+)
+
+// Requests are counted in fixed windows of rule.Window. A request is
+// allowed when the current window's count, plus the previous window's
+// count weighted by how much of it still overlaps the last rule.Window,
+// leaves room for it. This approximates a true sliding window with two
+// counters per client.
+//
+// The arithmetic is in whole milliseconds so Redis and memory agree
+// exactly: with window w and e elapsed, prev*(w-e)/w + cur is the estimate.
+
+// windowOf returns the index of the window holding now and how many
+// milliseconds into that window now is.
+func windowOf(rule Rule, now time.Time) (index, elapsed int64) {
+	size := rule.Window.Milliseconds()
+	ms := now.UnixMilli()
+	return ms / size, ms % size
+}
+
+func allows(rule Rule, elapsed, prev, cur int64) bool {
+	w := rule.Window.Milliseconds()
+	return prev*(w-elapsed)+(cur+1)*w <= int64(rule.Requests)*w
+}
+
+// newResult describes the counters after a request; cur includes it when
+// it was allowed.
+func newResult(rule Rule, elapsed, prev, cur int64, allowed bool) Result {
+	w := rule.Window.Milliseconds()
+	used := ceilDiv(prev*(w-elapsed)+cur*w, w)
+	result := Result{
+		Allowed:   allowed,
+		Limit:     rule.Requests,
+		Remaining: max(rule.Requests-int(used), 0),
+		Window:    rule.Window,
+		Reset:     time.Duration(w-elapsed) * time.Millisecond,
+	}
+	if !allowed {
+		result.RetryAfter = retryAfter(rule, elapsed, prev, cur)
+	}
+	return result
+}
+
+// retryAfter is how long until the estimate leaves room for one request.
+func retryAfter(rule Rule, elapsed, prev, cur int64) time.Duration {
+	w := rule.Window.Milliseconds()
+	limit := int64(rule.Requests)
+
+	// Room appears in this window once enough of the previous one has slid
+	// out: prev*(w-e) <= (limit-cur-1)*w
+	if cur+1 <= limit && prev > 0 {
+		at := w - (limit-cur-1)*w/prev
+		return time.Duration(max(at-elapsed, 1)) * time.Millisecond
+	}
+
+	// Otherwise in the next window, where this window's count becomes the
+	// previous one: cur*(w-e) <= (limit-1)*w
+	var at int64
+	if cur > 0 {
+		at = max(w-(limit-1)*w/cur, 0)
+	}
+	return time.Duration(w-elapsed+at) * time.Millisecond
+}
+
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,60 @@
+package repository_impl
+
+import (
+	"context"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditEventRepository(db *gorm.DB) interfaces.AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+func (r *auditEventRepository) Create(ctx context.Context, event *entity.AuditEvent) error {
+	return conn(ctx, r.db).Create(event).Error
+}
+
+func (r *auditEventRepository) List(ctx context.Context, filter interfaces.AuditEventFilter, limit, offset int) ([]entity.AuditEvent, int64, error) {
+	query := conn(ctx, r.db).Model(&entity.AuditEvent{})
+
+	if filter.ActorType != "" {
+		query = query.Where("actor_type = ?", filter.ActorType)
+	}
+	if filter.ActorID != "" {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []entity.AuditEvent
+	err := query.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&events).Error
+	return events, total, err
+}
@@ -0,0 +1,58 @@
+package repository_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewRecoveryCodeRepository(db *gorm.DB) interfaces.RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+// Replace discards every existing code for the user and stores the new set.
+func (r *recoveryCodeRepository) Replace(ctx context.Context, userID uint, codes []entity.RecoveryCode) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// Consume marks a matching unused code as used. The conditional update makes
+// redemption atomic, so a code cannot be spent twice by concurrent logins.
+func (r *recoveryCodeRepository) Consume(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *recoveryCodeRepository) CountUnused(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	err := conn(ctx, r.db).
+		Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *recoveryCodeRepository) DeleteByUserID(ctx context.Context, userID uint) error {
+	return conn(ctx, r.db).Where("user_id = ?", userID).Delete(&entity.RecoveryCode{}).Error
+}
@@ -0,0 +1,40 @@
+package repository_impl
+
+import (
+	"context"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/repository/interfaces"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) interfaces.Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// Nested calls join the outer transaction
+	if _, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return fn(ctx)
+	}
+
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// conn returns the transaction started by WithinTransaction, if ctx carries
+// one, or db otherwise. Repositories use it instead of db directly so that
+// they take part in a surrounding transaction.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -2,6 +2,7 @@ package repository_impl
 
 import (
 	"context"
+	"time"
 
 	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
 	"github.com/faizalnurrozi/go-starter-kit/internal/repository/interfaces"
@@ -18,12 +19,12 @@ func NewUserRepository(db *gorm.DB) interfaces.UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return conn(ctx, r.db).Create(user).Error
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id uint) (*entity.User, error) {
 	var user entity.User
-	err := r.db.WithContext(ctx).First(&user, id).Error
+	err := conn(ctx, r.db).First(&user, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +33,7 @@ func (r *userRepository) GetByID(ctx context.Context, id uint) (*entity.User, er
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user entity.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := conn(ctx, r.db).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
@@ -41,20 +42,66 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*entity.
 
 func (r *userRepository) GetAll(ctx context.Context, limit, offset int) ([]entity.User, error) {
 	var users []entity.User
-	err := r.db.WithContext(ctx).Limit(limit).Offset(offset).Find(&users).Error
+	err := conn(ctx, r.db).Limit(limit).Offset(offset).Find(&users).Error
 	return users, err
 }
 
 func (r *userRepository) Update(ctx context.Context, user *entity.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return conn(ctx, r.db).Save(user).Error
 }
 
 func (r *userRepository) Delete(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&entity.User{}, id).Error
+	return conn(ctx, r.db).Delete(&entity.User{}, id).Error
 }
 
 func (r *userRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&entity.User{}).Count(&count).Error
+	err := conn(ctx, r.db).Model(&entity.User{}).Count(&count).Error
 	return count, err
 }
+
+// UpdateTOTPLastStep records the last accepted TOTP time step, but only if it
+// advances the stored one. It reports false when another request already
+// consumed this (or a later) step.
+func (r *userRepository) UpdateTOTPLastStep(ctx context.Context, id uint, step int64) (bool, error) {
+	result := conn(ctx, r.db).
+		Model(&entity.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RecordMFAFailure increments the count in the database, so concurrent
+// attempts are all counted.
+func (r *userRepository) RecordMFAFailure(ctx context.Context, id uint) (int, error) {
+	err := conn(ctx, r.db).
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		UpdateColumn("mfa_failed_attempts", gorm.Expr("mfa_failed_attempts + 1")).Error
+	if err != nil {
+		return 0, err
+	}
+
+	var user entity.User
+	if err := conn(ctx, r.db).Select("mfa_failed_attempts").First(&user, id).Error; err != nil {
+		return 0, err
+	}
+	return user.MFAFailedAttempts, nil
+}
+
+func (r *userRepository) LockMFA(ctx context.Context, id uint, until time.Time) error {
+	return conn(ctx, r.db).
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"mfa_failed_attempts": 0, "mfa_locked_until": until}).Error
+}
+
+func (r *userRepository) ResetMFAFailures(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).
+		Model(&entity.User{}).
+		Where("id = ?", id).
+		UpdateColumn("mfa_failed_attempts", 0).Error
+}
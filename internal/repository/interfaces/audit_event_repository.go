@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+)
+
+// AuditEventFilter narrows List results; zero-valued fields are ignored.
+type AuditEventFilter struct {
+	ActorType  string
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+}
+
+// AuditEventRepository is append-only: events can be created and read but
+// never updated or deleted through the application.
+type AuditEventRepository interface {
+	Create(ctx context.Context, event *entity.AuditEvent) error
+	List(ctx context.Context, filter AuditEventFilter, limit, offset int) ([]entity.AuditEvent, int64, error)
+}
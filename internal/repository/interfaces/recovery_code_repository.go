@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+)
+
+type RecoveryCodeRepository interface {
+	Replace(ctx context.Context, userID uint, codes []entity.RecoveryCode) error
+	Consume(ctx context.Context, userID uint, codeHash string) (bool, error)
+	CountUnused(ctx context.Context, userID uint) (int64, error)
+	DeleteByUserID(ctx context.Context, userID uint) error
+}
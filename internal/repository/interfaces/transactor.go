@@ -0,0 +1,10 @@
+package interfaces
+
+import "context"
+
+// Transactor runs fn in a database transaction. Repository calls made with
+// the context passed to fn join that transaction; the transaction commits if
+// fn returns nil and rolls back otherwise.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
 )
@@ -14,4 +15,11 @@ type UserRepository interface {
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id uint) error
 	Count(ctx context.Context) (int64, error)
+	UpdateTOTPLastStep(ctx context.Context, id uint, step int64) (bool, error)
+	// RecordMFAFailure counts a wrong second-factor code and returns the
+	// count since the last success or lock.
+	RecordMFAFailure(ctx context.Context, id uint) (int, error)
+	// LockMFA refuses second-factor codes until until and restarts the count.
+	LockMFA(ctx context.Context, id uint, until time.Time) error
+	ResetMFAFailures(ctx context.Context, id uint) error
 }
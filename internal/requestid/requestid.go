@@ -0,0 +1,50 @@
+// Package requestid carries the ID that ties together the logs, audit
+// events and responses of a single request.
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header, and lower-cased the gRPC metadata key, that
+// carries the request ID in both directions.
+const Header = "X-Request-ID"
+
+const maxLength = 128
+
+type contextKey struct{}
+
+// Resolve returns id if it is acceptable as a client-supplied request ID,
+// otherwise a freshly generated one.
+func Resolve(id string) string {
+	if valid(id) {
+		return id
+	}
+	return uuid.NewString()
+}
+
+// valid accepts up to 128 printable ASCII characters, so the ID is safe to
+// echo in headers and log lines.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,105 @@
+package serviceimpl
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	response "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/repository/interfaces"
+	iUc "github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
+)
+
+type auditService struct {
+	auditRepo interfaces.AuditEventRepository
+}
+
+func NewAuditService(auditRepo interfaces.AuditEventRepository) iUc.AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
+
+// Record stores entry. Callers run it inside the same Transactor call as the
+// mutation it describes, so the event and the change commit or roll back
+// together; the returned error should abort that transaction.
+func (s *auditService) Record(ctx context.Context, entry audit.Entry) error {
+	actor := audit.ActorFromContext(ctx)
+	if entry.Actor != nil {
+		actor = *entry.Actor
+	}
+	metadata := audit.MetadataFromContext(ctx)
+
+	changes, err := json.Marshal(audit.Diff(entry.Before, entry.After))
+	if err != nil {
+		return err
+	}
+
+	return s.auditRepo.Create(ctx, &entity.AuditEvent{
+		ActorType:  actor.Type,
+		ActorID:    actor.ID,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   strconv.FormatUint(uint64(entry.TargetID), 10),
+		Changes:    string(changes),
+		RequestID:  metadata.RequestID,
+		IP:         metadata.IP,
+		UserAgent:  truncate(metadata.UserAgent, 255),
+	})
+}
+
+func (s *auditService) List(ctx context.Context, req *dto.ListAuditEventsRequest) (*response.AuditEventListResponse, error) {
+	filter := interfaces.AuditEventFilter{
+		ActorType:  req.ActorType,
+		ActorID:    req.ActorID,
+		Action:     req.Action,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		RequestID:  req.RequestID,
+	}
+
+	var err error
+	if filter.From, err = parseTimeFilter(req.From); err != nil {
+		return nil, errors.NewValidationError("Invalid from timestamp")
+	}
+	if filter.To, err = parseTimeFilter(req.To); err != nil {
+		return nil, errors.NewValidationError("Invalid to timestamp")
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+
+	events, total, err := s.auditRepo.List(ctx, filter, limit, req.Offset)
+	if err != nil {
+		log.Error(ctx, "Error listing audit events", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to list audit events")
+	}
+
+	return response.NewAuditEventListResponse(events, total, limit, req.Offset), nil
+}
+
+func parseTimeFilter(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
@@ -0,0 +1,223 @@
+package serviceimpl
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	response "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/repository/interfaces"
+	iUc "github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type authService struct {
+	userRepo           interfaces.UserRepository
+	recoveryRepo       interfaces.RecoveryCodeRepository
+	jwtConfig          config.JWTConfig
+	mfaConfig          config.MFAConfig
+	verificationConfig config.VerificationConfig
+}
+
+func NewAuthService(userRepo interfaces.UserRepository, recoveryRepo interfaces.RecoveryCodeRepository, jwtConfig config.JWTConfig, mfaConfig config.MFAConfig, verificationConfig config.VerificationConfig) iUc.AuthService {
+	return &authService{
+		userRepo:           userRepo,
+		recoveryRepo:       recoveryRepo,
+		jwtConfig:          jwtConfig,
+		mfaConfig:          mfaConfig,
+		verificationConfig: verificationConfig,
+	}
+}
+
+func (s *authService) Login(ctx context.Context, req *dto.LoginRequest) (*response.LoginResponse, error) {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewAppError(http.StatusUnauthorized, "Invalid email or password")
+		}
+		log.Error(ctx, "Error getting user", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to get user")
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return nil, errors.NewAppError(http.StatusUnauthorized, "Invalid email or password")
+	}
+
+	if !user.IsActive {
+		return nil, errors.NewForbiddenError("Account is disabled")
+	}
+	if s.verificationConfig.RequireVerifiedEmail && !user.EmailVerified() {
+		return nil, errors.NewForbiddenError("Email address has not been verified")
+	}
+
+	// Users with a confirmed second factor must present it before getting
+	// an access token; users whose role requires one must enroll first.
+	if user.MFAEnabled() {
+		return s.pendingResponse(ctx, user, auth.PurposeMFA)
+	}
+	if roleRequiresMFA(s.mfaConfig.RequiredRoles, user.Role) {
+		return s.pendingResponse(ctx, user, auth.PurposeMFAEnroll)
+	}
+
+	return s.accessResponse(ctx, user)
+}
+
+func (s *authService) VerifyMFA(ctx context.Context, req *dto.VerifyMFARequest) (*response.LoginResponse, error) {
+	claims, err := auth.ParseToken(s.jwtConfig.Secret.Value(), req.MFAToken)
+	if err != nil || claims.Purpose != auth.PurposeMFA {
+		return nil, errors.NewAppError(http.StatusUnauthorized, "Invalid or expired MFA token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewAppError(http.StatusUnauthorized, "Invalid or expired MFA token")
+		}
+		log.Error(ctx, "Error getting user", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to get user")
+	}
+
+	if !user.IsActive {
+		return nil, errors.NewForbiddenError("Account is disabled")
+	}
+	if !user.MFAEnabled() {
+		return nil, errors.NewAppError(http.StatusUnauthorized, "Invalid or expired MFA token")
+	}
+	if user.MFALocked(time.Now()) {
+		return nil, tooManyMFAFailures()
+	}
+
+	var verified bool
+	if req.Code != "" {
+		verified, err = checkTOTP(ctx, s.userRepo, user, req.Code)
+	} else {
+		verified, err = s.recoveryRepo.Consume(ctx, user.ID, auth.HashRecoveryCode(req.RecoveryCode))
+		if verified {
+			log.Warn(ctx, "Recovery code used to sign in",
+				logger.Uint("user_id", user.ID),
+				logger.String("action", "mfa_recovery_code_used"),
+			)
+		}
+	}
+	if err != nil {
+		log.Error(ctx, "Error verifying second factor", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to verify second factor")
+	}
+	if !verified {
+		return nil, s.recordMFAFailure(ctx, user)
+	}
+
+	if user.MFAFailedAttempts > 0 {
+		if err := s.userRepo.ResetMFAFailures(ctx, user.ID); err != nil {
+			log.Error(ctx, "Error resetting second factor failures", logger.Err(err))
+		}
+	}
+	return s.accessResponse(ctx, user)
+}
+
+// Authorize requires userID's account to be active, to hold one of roles
+// and to have the second factor its role requires. It reads the account on
+// every call, so demotions and MFA resets apply before issued tokens expire.
+func (s *authService) Authorize(ctx context.Context, userID uint, roles ...string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NewUnauthorizedError()
+		}
+		log.Error(ctx, "Error getting user", logger.Err(err))
+		return errors.NewInternalError("Failed to get user")
+	}
+
+	if !user.IsActive {
+		return errors.NewForbiddenError("Account is disabled")
+	}
+	if !slices.Contains(roles, user.Role) {
+		return errors.NewForbiddenError("Forbidden")
+	}
+	if roleRequiresMFA(s.mfaConfig.RequiredRoles, user.Role) && !user.MFAEnabled() {
+		return errors.NewForbiddenError("Two-factor authentication is required for your role")
+	}
+	return nil
+}
+
+func tooManyMFAFailures() error {
+	return errors.NewAppError(http.StatusTooManyRequests, "Too many invalid verification codes, try again later")
+}
+
+// recordMFAFailure counts a wrong code against user, locking verification
+// once mfa.max_failed_attempts is reached, and returns the error to send.
+func (s *authService) recordMFAFailure(ctx context.Context, user *entity.User) error {
+	failures, err := s.userRepo.RecordMFAFailure(ctx, user.ID)
+	if err != nil {
+		log.Error(ctx, "Error recording second factor failure", logger.Err(err))
+		return errors.NewInternalError("Failed to verify second factor")
+	}
+	if failures < s.mfaConfig.MaxFailedAttempts {
+		return errors.NewAppError(http.StatusUnauthorized, "Invalid verification code")
+	}
+
+	until := time.Now().Add(time.Duration(s.mfaConfig.Lockout) * time.Minute)
+	if err := s.userRepo.LockMFA(ctx, user.ID, until); err != nil {
+		log.Error(ctx, "Error locking second factor", logger.Err(err))
+		return errors.NewInternalError("Failed to verify second factor")
+	}
+	log.Warn(ctx, "Second factor locked after invalid codes",
+		logger.Uint("user_id", user.ID),
+		logger.Int("failures", failures),
+		logger.String("action", "mfa_locked"),
+	)
+	return tooManyMFAFailures()
+}
+
+func (s *authService) accessResponse(ctx context.Context, user *entity.User) (*response.LoginResponse, error) {
+	ttl := time.Duration(s.jwtConfig.Expire) * time.Hour
+	token, expiresAt, err := auth.GenerateToken(s.jwtConfig.Secret.Value(), tokenClaims(user, auth.PurposeAccess), ttl)
+	if err != nil {
+		log.Error(ctx, "Error signing token", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to issue token")
+	}
+
+	log.Info(ctx, "User signed in",
+		logger.Uint("user_id", user.ID),
+		logger.String("action", "login"),
+	)
+
+	return &response.LoginResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresAt:   &expiresAt,
+	}, nil
+}
+
+func (s *authService) pendingResponse(ctx context.Context, user *entity.User, purpose string) (*response.LoginResponse, error) {
+	ttl := time.Duration(s.mfaConfig.PendingTokenExpire) * time.Minute
+	token, _, err := auth.GenerateToken(s.jwtConfig.Secret.Value(), tokenClaims(user, purpose), ttl)
+	if err != nil {
+		log.Error(ctx, "Error signing token", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to issue token")
+	}
+
+	return &response.LoginResponse{
+		MFARequired:           true,
+		MFAEnrollmentRequired: purpose == auth.PurposeMFAEnroll,
+		MFAToken:              token,
+	}, nil
+}
+
+func tokenClaims(user *entity.User, purpose string) auth.Claims {
+	return auth.Claims{
+		UserID:  user.ID,
+		Email:   user.Email,
+		Role:    user.Role,
+		Purpose: purpose,
+	}
+}
@@ -0,0 +1,250 @@
+package serviceimpl
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/mailer"
+	"github.com/faizalnurrozi/go-starter-kit/internal/repository/interfaces"
+	iUc "github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
+
+	"gorm.io/gorm"
+)
+
+type emailVerificationService struct {
+	userRepo           interfaces.UserRepository
+	cache              *userCache
+	mailer             mailer.Mailer
+	tx                 interfaces.Transactor
+	auditor            iUc.AuditService
+	jwtConfig          config.JWTConfig
+	verificationConfig config.VerificationConfig
+}
+
+func NewEmailVerificationService(userRepo interfaces.UserRepository, c cache.Cache, cacheConfig config.CacheConfig, mailer mailer.Mailer, tx interfaces.Transactor, auditor iUc.AuditService, jwtConfig config.JWTConfig, verificationConfig config.VerificationConfig) iUc.EmailVerificationService {
+	return &emailVerificationService{
+		userRepo:           userRepo,
+		cache:              newUserCache(c, cacheConfig),
+		mailer:             mailer,
+		tx:                 tx,
+		auditor:            auditor,
+		jwtConfig:          jwtConfig,
+		verificationConfig: verificationConfig,
+	}
+}
+
+// SendVerification mails a signed link for the user's current address. The
+// token embeds the address, so it stops working if the email changes.
+func (s *emailVerificationService) SendVerification(ctx context.Context, user *entity.User) error {
+	ttl := time.Duration(s.verificationConfig.TokenExpire) * time.Hour
+	token, _, err := auth.GenerateToken(s.jwtConfig.Secret.Value(), auth.Claims{
+		UserID:  user.ID,
+		Email:   user.Email,
+		Purpose: auth.PurposeEmailVerify,
+	}, ttl)
+	if err != nil {
+		return err
+	}
+
+	link := s.verificationConfig.URL + "?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %d hours. If you did not create an account, you can ignore this email.\n",
+		user.Name, link, s.verificationConfig.TokenExpire)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body:    body,
+	})
+}
+
+func (s *emailVerificationService) VerifyEmail(ctx context.Context, req *dto.VerifyEmailRequest) error {
+	claims, err := auth.ParseToken(s.jwtConfig.Secret.Value(), req.Token)
+	if err != nil || claims.Purpose != auth.PurposeEmailVerify {
+		return errors.NewValidationError("Invalid or expired verification token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NewValidationError("Invalid or expired verification token")
+		}
+		log.Error(ctx, "Error getting user", logger.Err(err))
+		return errors.NewInternalError("Failed to get user")
+	}
+
+	if user.Email != claims.Email {
+		return errors.NewValidationError("Invalid or expired verification token")
+	}
+	if user.EmailVerified() {
+		return nil
+	}
+
+	before := audit.Snapshot(user)
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+
+	// The link proves control of the address, so the user is the actor
+	// even though the request carries no access token
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.Entry{
+			Action:     audit.ActionUserEmailVerified,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Before:     before,
+			After:      audit.Snapshot(user),
+			Actor:      audit.UserActor(user.ID),
+		})
+	})
+	if err != nil {
+		log.Error(ctx, "Error updating user", logger.Err(err))
+		return errors.NewInternalError("Failed to verify email")
+	}
+
+	s.cache.invalidate(ctx, user.ID)
+
+	log.Info(ctx, "Email address verified",
+		logger.Uint("user_id", user.ID),
+		logger.String("action", "email_verified"),
+	)
+
+	return nil
+}
+
+// ResendVerification sends a new link if the address belongs to an
+// unverified account. It succeeds silently otherwise so the endpoint cannot
+// be used to discover registered addresses.
+func (s *emailVerificationService) ResendVerification(ctx context.Context, req *dto.ResendVerificationRequest) error {
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		log.Error(ctx, "Error getting user", logger.Err(err))
+		return errors.NewInternalError("Failed to get user")
+	}
+
+	if user.EmailVerified() {
+		return nil
+	}
+
+	if err := s.SendVerification(ctx, user); err != nil {
+		log.Error(ctx, "Error sending verification email", logger.Err(err))
+		return errors.NewAppError(http.StatusServiceUnavailable, "Failed to send verification email")
+	}
+	return nil
+}
+
+// SendEmailChange asks the user to confirm user.PendingEmail from that
+// address and tells the current address that a change was requested.
+func (s *emailVerificationService) SendEmailChange(ctx context.Context, user *entity.User) error {
+	ttl := time.Duration(s.verificationConfig.TokenExpire) * time.Hour
+	token, _, err := auth.GenerateToken(s.jwtConfig.Secret.Value(), auth.Claims{
+		UserID:  user.ID,
+		Email:   user.PendingEmail,
+		Purpose: auth.PurposeEmailChange,
+	}, ttl)
+	if err != nil {
+		return err
+	}
+
+	link := s.verificationConfig.URL + "?type=email_change&token=" + url.QueryEscape(token)
+	confirmBody := fmt.Sprintf("Hi %s,\n\nPlease confirm that you want to use this address for your account by opening the link below:\n\n%s\n\nThe link expires in %d hours. If you did not request this change, you can ignore this email.\n",
+		user.Name, link, s.verificationConfig.TokenExpire)
+
+	if err := s.mailer.Send(ctx, mailer.Message{
+		To:      user.PendingEmail,
+		Subject: "Confirm your new email address",
+		Body:    confirmBody,
+	}); err != nil {
+		return err
+	}
+
+	noticeBody := fmt.Sprintf("Hi %s,\n\nA request was made to change the email address on your account to %s. The change takes effect once it is confirmed from the new address.\n\nIf you did not make this request, please change your password and contact support.\n",
+		user.Name, user.PendingEmail)
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Your email address is being changed",
+		Body:    noticeBody,
+	})
+}
+
+func (s *emailVerificationService) ConfirmEmailChange(ctx context.Context, req *dto.ConfirmEmailChangeRequest) error {
+	claims, err := auth.ParseToken(s.jwtConfig.Secret.Value(), req.Token)
+	if err != nil || claims.Purpose != auth.PurposeEmailChange {
+		return errors.NewValidationError("Invalid or expired confirmation token")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NewValidationError("Invalid or expired confirmation token")
+		}
+		log.Error(ctx, "Error getting user", logger.Err(err))
+		return errors.NewInternalError("Failed to get user")
+	}
+
+	// A newer request replaces PendingEmail, which invalidates older links
+	if user.PendingEmail == "" || user.PendingEmail != claims.Email {
+		return errors.NewValidationError("Invalid or expired confirmation token")
+	}
+
+	// The address may have been taken since the change was requested
+	if _, err := s.userRepo.GetByEmail(ctx, user.PendingEmail); err == nil {
+		return errors.NewConflictError("Email already in use")
+	} else if err != gorm.ErrRecordNotFound {
+		log.Error(ctx, "Error checking existing user", logger.Err(err))
+		return errors.NewInternalError("Failed to check existing user")
+	}
+
+	before := audit.Snapshot(user)
+	now := time.Now()
+	user.Email = user.PendingEmail
+	user.PendingEmail = ""
+	user.EmailVerifiedAt = &now
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.Entry{
+			Action:     audit.ActionUserEmailChanged,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Before:     before,
+			After:      audit.Snapshot(user),
+			Actor:      audit.UserActor(user.ID),
+		})
+	})
+	if err != nil {
+		if stderrors.Is(err, gorm.ErrDuplicatedKey) {
+			return errors.NewConflictError("Email already in use")
+		}
+		log.Error(ctx, "Error updating user", logger.Err(err))
+		return errors.NewInternalError("Failed to change email")
+	}
+
+	s.cache.invalidate(ctx, user.ID)
+
+	log.Info(ctx, "Email address changed",
+		logger.Uint("user_id", user.ID),
+		logger.String("action", "email_changed"),
+	)
+
+	return nil
+}
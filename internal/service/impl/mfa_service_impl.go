@@ -0,0 +1,307 @@
+package serviceimpl
+
+import (
+	"context"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	response "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/repository/interfaces"
+	iUc "github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
+
+	"gorm.io/gorm"
+)
+
+type mfaService struct {
+	userRepo     interfaces.UserRepository
+	recoveryRepo interfaces.RecoveryCodeRepository
+	tx           interfaces.Transactor
+	auditor      iUc.AuditService
+	mfaConfig    config.MFAConfig
+}
+
+func NewMFAService(userRepo interfaces.UserRepository, recoveryRepo interfaces.RecoveryCodeRepository, tx interfaces.Transactor, auditor iUc.AuditService, mfaConfig config.MFAConfig) iUc.MFAService {
+	return &mfaService{
+		userRepo:     userRepo,
+		recoveryRepo: recoveryRepo,
+		tx:           tx,
+		auditor:      auditor,
+		mfaConfig:    mfaConfig,
+	}
+}
+
+// EnrollTOTP starts (or restarts) enrollment by generating a new secret. The
+// factor is not enforced until ConfirmTOTP succeeds.
+func (s *mfaService) EnrollTOTP(ctx context.Context, userID uint) (*response.TOTPEnrollmentResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.MFAEnabled() {
+		return nil, errors.NewBusinessError("Two-factor authentication is already enabled")
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		log.Error(ctx, "Error generating TOTP secret", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to start enrollment")
+	}
+
+	user.ClearMFA()
+	user.TOTPSecret = secret
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		log.Error(ctx, "Error updating user", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to start enrollment")
+	}
+
+	return &response.TOTPEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(s.mfaConfig.Issuer, user.Email, secret),
+	}, nil
+}
+
+func (s *mfaService) ConfirmTOTP(ctx context.Context, userID uint, req *dto.TOTPCodeRequest) (*response.RecoveryCodesResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.MFAEnabled() {
+		return nil, errors.NewBusinessError("Two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.NewBusinessError("Two-factor enrollment has not been started")
+	}
+
+	if err := s.verifyCode(ctx, user, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, records, err := newRecoveryCodes(user.ID, s.mfaConfig.RecoveryCodeCount)
+	if err != nil {
+		log.Error(ctx, "Error generating recovery codes", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to generate recovery codes")
+	}
+
+	before := audit.Snapshot(user)
+	now := time.Now()
+	user.TOTPEnabledAt = &now
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		if err := s.recoveryRepo.Replace(ctx, user.ID, records); err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.Entry{
+			Action:     audit.ActionUserMFAEnabled,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Before:     before,
+			After:      audit.Snapshot(user),
+		})
+	})
+	if err != nil {
+		log.Error(ctx, "Error enabling two-factor authentication", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to enable two-factor authentication")
+	}
+
+	log.Info(ctx, "Two-factor authentication enabled",
+		logger.Uint("user_id", user.ID),
+		logger.String("action", "mfa_enabled"),
+	)
+
+	return &response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *mfaService) DisableTOTP(ctx context.Context, userID uint, req *dto.TOTPCodeRequest) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !user.MFAEnabled() {
+		return errors.NewBusinessError("Two-factor authentication is not enabled")
+	}
+	if roleRequiresMFA(s.mfaConfig.RequiredRoles, user.Role) {
+		return errors.NewForbiddenError("Two-factor authentication is required for your role")
+	}
+
+	if err := s.verifyCode(ctx, user, req.Code); err != nil {
+		return err
+	}
+
+	if err := s.clear(ctx, user, audit.ActionUserMFADisabled); err != nil {
+		return err
+	}
+
+	log.Info(ctx, "Two-factor authentication disabled",
+		logger.Uint("user_id", user.ID),
+		logger.String("action", "mfa_disabled"),
+	)
+
+	return nil
+}
+
+func (s *mfaService) RegenerateRecoveryCodes(ctx context.Context, userID uint, req *dto.TOTPCodeRequest) (*response.RecoveryCodesResponse, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.MFAEnabled() {
+		return nil, errors.NewBusinessError("Two-factor authentication is not enabled")
+	}
+
+	if err := s.verifyCode(ctx, user, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, records, err := newRecoveryCodes(user.ID, s.mfaConfig.RecoveryCodeCount)
+	if err != nil {
+		log.Error(ctx, "Error generating recovery codes", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to generate recovery codes")
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.recoveryRepo.Replace(ctx, user.ID, records); err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.Entry{
+			Action:     audit.ActionUserRecoveryCodes,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+		})
+	})
+	if err != nil {
+		log.Error(ctx, "Error storing recovery codes", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to generate recovery codes")
+	}
+
+	return &response.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Reset removes a user's second factor without requiring a code. It is meant
+// for administrators helping a user who lost both device and recovery codes.
+func (s *mfaService) Reset(ctx context.Context, userID uint) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.clear(ctx, user, audit.ActionUserMFAReset); err != nil {
+		return err
+	}
+
+	log.Warn(ctx, "Two-factor authentication reset by administrator",
+		logger.Uint("user_id", user.ID),
+		logger.String("action", "mfa_reset"),
+	)
+
+	return nil
+}
+
+func (s *mfaService) getUser(ctx context.Context, userID uint) (*entity.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewNotFoundError("User")
+		}
+		log.Error(ctx, "Error getting user", logger.Err(err))
+		return nil, errors.NewInternalError("Failed to get user")
+	}
+	return user, nil
+}
+
+func (s *mfaService) verifyCode(ctx context.Context, user *entity.User, code string) error {
+	verified, err := checkTOTP(ctx, s.userRepo, user, code)
+	if err != nil {
+		log.Error(ctx, "Error verifying TOTP code", logger.Err(err))
+		return errors.NewInternalError("Failed to verify code")
+	}
+	if !verified {
+		return errors.NewValidationError("Invalid verification code")
+	}
+	return nil
+}
+
+// clear removes the user's TOTP enrollment and recovery codes, recording
+// action in the audit log.
+func (s *mfaService) clear(ctx context.Context, user *entity.User, action string) error {
+	before := audit.Snapshot(user)
+	user.ClearMFA()
+
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		if err := s.recoveryRepo.DeleteByUserID(ctx, user.ID); err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.Entry{
+			Action:     action,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Before:     before,
+			After:      audit.Snapshot(user),
+		})
+	})
+	if err != nil {
+		log.Error(ctx, "Error disabling two-factor authentication", logger.Err(err))
+		return errors.NewInternalError("Failed to disable two-factor authentication")
+	}
+	return nil
+}
+
+// newRecoveryCodes generates count codes, returning the plain codes to show
+// the user once and the hashed records to store.
+func newRecoveryCodes(userID uint, count int) ([]string, []entity.RecoveryCode, error) {
+	codes, err := auth.GenerateRecoveryCodes(count)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records := make([]entity.RecoveryCode, len(codes))
+	for i, code := range codes {
+		records[i] = entity.RecoveryCode{
+			UserID:   userID,
+			CodeHash: auth.HashRecoveryCode(code),
+		}
+	}
+	return codes, records, nil
+}
+
+// checkTOTP validates code for user and records the matched time step so the
+// same code cannot be used twice.
+func checkTOTP(ctx context.Context, userRepo interfaces.UserRepository, user *entity.User, code string) (bool, error) {
+	step, ok := auth.ValidateTOTP(user.TOTPSecret, code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		return false, nil
+	}
+
+	recorded, err := userRepo.UpdateTOTPLastStep(ctx, user.ID, step)
+	if err != nil {
+		return false, err
+	}
+	if recorded {
+		user.TOTPLastStep = step
+	}
+	return recorded, nil
+}
+
+func roleRequiresMFA(requiredRoles []string, role string) bool {
+	for _, r := range requiredRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -2,10 +2,11 @@ package serviceimpl
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"time"
 
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
 	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
 	response "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
 	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
@@ -14,29 +15,36 @@ import (
 	"github.com/faizalnurrozi/go-starter-kit/internal/repository/interfaces"
 	iUc "github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
 
-	"github.com/redis/go-redis/v9"
-	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
+var log = logger.Named("service")
+
 type userService struct {
 	userRepo interfaces.UserRepository
-	redis    *redis.Client
+	cache    *userCache
+	verifier iUc.EmailVerificationService
+	tx       interfaces.Transactor
+	auditor  iUc.AuditService
 }
 
-func NewUserService(userRepo interfaces.UserRepository, redis *redis.Client) iUc.UserService {
+// NewUserService caches users in c; pass cache.NewNoop() to disable caching.
+func NewUserService(userRepo interfaces.UserRepository, c cache.Cache, cacheConfig config.CacheConfig, verifier iUc.EmailVerificationService, tx interfaces.Transactor, auditor iUc.AuditService) iUc.UserService {
 	return &userService{
 		userRepo: userRepo,
-		redis:    redis,
+		cache:    newUserCache(c, cacheConfig),
+		verifier: verifier,
+		tx:       tx,
+		auditor:  auditor,
 	}
 }
 
 func (s *userService) Create(ctx context.Context, req *dto.CreateUserRequest) (*response.UserResponse, error) {
-	logger.WithFields(logrus.Fields{
-		"email":  req.Email,
-		"action": "create_user",
-	}).Info("Creating new user")
+	log.Info(ctx, "Creating new user",
+		logger.String("email", req.Email),
+		logger.String("action", "create_user"),
+	)
 
 	// Check if user already exists
 	_, err := s.userRepo.GetByEmail(ctx, req.Email)
@@ -44,14 +52,14 @@ func (s *userService) Create(ctx context.Context, req *dto.CreateUserRequest) (*
 		return nil, errors.NewBusinessError("Email already exists")
 	}
 	if err != gorm.ErrRecordNotFound {
-		logger.Error("Error checking existing user: ", err)
+		log.Error(ctx, "Error checking existing user", logger.Err(err))
 		return nil, errors.NewInternalError("Failed to check existing user")
 	}
 
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		logger.Error("Error hashing password: ", err)
+		log.Error(ctx, "Error hashing password", logger.Err(err))
 		return nil, errors.NewInternalError("Failed to hash password")
 	}
 
@@ -59,50 +67,68 @@ func (s *userService) Create(ctx context.Context, req *dto.CreateUserRequest) (*
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: string(hashedPassword),
+		Role:     entity.RoleUser,
 		IsActive: true,
 	}
 
-	if err := s.userRepo.Create(ctx, user); err != nil {
-		logger.Error("Error creating user: ", err)
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.Entry{
+			Action:     audit.ActionUserCreate,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			After:      audit.Snapshot(user),
+		})
+	})
+	if err != nil {
+		log.Error(ctx, "Error creating user", logger.Err(err))
 		return nil, errors.NewInternalError("Failed to create user")
 	}
 
 	// Cache user
-	s.cacheUser(ctx, user)
+	s.cache.set(ctx, user)
 
-	logger.WithFields(logrus.Fields{
-		"user_id": user.ID,
-		"email":   user.Email,
-	}).Info("User created successfully")
+	// A failed send is not fatal: the user can ask for the link again
+	if s.verifier != nil {
+		if err := s.verifier.SendVerification(ctx, user); err != nil {
+			log.Error(ctx, "Error sending verification email", logger.Err(err))
+		}
+	}
+
+	log.Info(ctx, "User created successfully",
+		logger.Uint("user_id", user.ID),
+		logger.String("email", user.Email),
+	)
 
 	return response.NewUserResponse(user), nil
 }
 
 func (s *userService) GetByID(ctx context.Context, id uint) (*response.UserResponse, error) {
-	// Try to get from cache first
-	if user := s.getCachedUser(ctx, id); user != nil {
-		return response.NewUserResponse(user), nil
-	}
-
-	user, err := s.userRepo.GetByID(ctx, id)
-	if err != nil {
+	// Concurrent misses share one query; missing IDs are cached briefly
+	user, err := s.cache.get(ctx, id, func(ctx context.Context) (*entity.User, error) {
+		user, err := s.userRepo.GetByID(ctx, id)
 		if err == gorm.ErrRecordNotFound {
+			return nil, cache.ErrNotFound
+		}
+		return user, err
+	})
+	if err != nil {
+		if err == cache.ErrNotFound {
 			return nil, errors.NewNotFoundError("User")
 		}
-		logger.Error("Error getting user: ", err)
+		log.Error(ctx, "Error getting user", logger.Err(err))
 		return nil, errors.NewInternalError("Failed to get user")
 	}
 
-	// Cache user
-	s.cacheUser(ctx, user)
-
-	return response.NewUserResponse(user), nil
+	return user.response(), nil
 }
 
 func (s *userService) GetAll(ctx context.Context, limit, offset int) ([]*response.UserResponse, error) {
 	users, err := s.userRepo.GetAll(ctx, limit, offset)
 	if err != nil {
-		logger.Error("Error getting users: ", err)
+		log.Error(ctx, "Error getting users", logger.Err(err))
 		return nil, errors.NewInternalError("Failed to get users")
 	}
 
@@ -110,92 +136,153 @@ func (s *userService) GetAll(ctx context.Context, limit, offset int) ([]*respons
 }
 
 func (s *userService) Update(ctx context.Context, id uint, req *dto.UpdateUserRequest) (*response.UserResponse, error) {
+	if req.Email != nil {
+		if err := s.authorizeEmailChange(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+
 	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, errors.NewNotFoundError("User")
 		}
-		logger.Error("Error getting user: ", err)
+		log.Error(ctx, "Error getting user", logger.Err(err))
 		return nil, errors.NewInternalError("Failed to get user")
 	}
 
+	before := audit.Snapshot(user)
+
 	// Update fields if provided
 	if req.Name != nil {
 		user.Name = *req.Name
 	}
-	if req.Email != nil {
-		user.Email = *req.Email
-	}
 	if req.IsActive != nil {
 		user.IsActive = *req.IsActive
 	}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
-		logger.Error("Error updating user: ", err)
+	// A new email is only stored as pending; it replaces the current one
+	// once confirmed from the new address. Sending the current address
+	// cancels a pending change.
+	emailChanged := false
+	if req.Email != nil {
+		switch *req.Email {
+		case user.Email:
+			user.PendingEmail = ""
+		case user.PendingEmail:
+		default:
+			if err := s.checkEmailAvailable(ctx, *req.Email); err != nil {
+				return nil, err
+			}
+			user.PendingEmail = *req.Email
+			emailChanged = true
+		}
+	}
+
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.Entry{
+			Action:     audit.ActionUserUpdate,
+			TargetType: audit.TargetUser,
+			TargetID:   user.ID,
+			Before:     before,
+			After:      audit.Snapshot(user),
+		})
+	})
+	if err != nil {
+		log.Error(ctx, "Error updating user", logger.Err(err))
 		return nil, errors.NewInternalError("Failed to update user")
 	}
 
 	// Invalidate cache
-	s.invalidateUserCache(ctx, id)
+	s.cache.invalidate(ctx, id)
+
+	if emailChanged {
+		if s.verifier != nil {
+			if err := s.verifier.SendEmailChange(ctx, user); err != nil {
+				log.Error(ctx, "Error sending email change confirmation", logger.Err(err))
+			}
+		}
+
+		log.Info(ctx, "Email change requested",
+			logger.Uint("user_id", user.ID),
+			logger.String("action", "email_change_requested"),
+		)
+	}
 
 	return response.NewUserResponse(user), nil
 }
 
+// authorizeEmailChange lets users change only their own email address
+// unless they are an admin, going by their current role rather than the
+// one in their token. Operator tools with a system principal are not
+// restricted; calls without any principal are refused.
+func (s *userService) authorizeEmailChange(ctx context.Context, id uint) error {
+	principal, ok := auth.PrincipalFromContext(ctx)
+	if !ok {
+		return errors.NewUnauthorizedError()
+	}
+	if principal.System != "" || principal.UserID == id {
+		return nil
+	}
+
+	forbidden := errors.NewForbiddenError("Only admins can change another user's email address")
+	caller, err := s.userRepo.GetByID(ctx, principal.UserID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return forbidden
+		}
+		log.Error(ctx, "Error getting user", logger.Err(err))
+		return errors.NewInternalError("Failed to update user")
+	}
+	if caller.Role != entity.RoleAdmin {
+		return forbidden
+	}
+	return nil
+}
+
 func (s *userService) Delete(ctx context.Context, id uint) error {
-	_, err := s.userRepo.GetByID(ctx, id)
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return errors.NewNotFoundError("User")
 		}
-		logger.Error("Error getting user: ", err)
+		log.Error(ctx, "Error getting user", logger.Err(err))
 		return errors.NewInternalError("Failed to get user")
 	}
 
-	if err := s.userRepo.Delete(ctx, id); err != nil {
-		logger.Error("Error deleting user: ", err)
+	err = s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.Delete(ctx, id); err != nil {
+			return err
+		}
+		return s.auditor.Record(ctx, audit.Entry{
+			Action:     audit.ActionUserDelete,
+			TargetType: audit.TargetUser,
+			TargetID:   id,
+			Before:     audit.Snapshot(user),
+		})
+	})
+	if err != nil {
+		log.Error(ctx, "Error deleting user", logger.Err(err))
 		return errors.NewInternalError("Failed to delete user")
 	}
 
 	// Invalidate cache
-	s.invalidateUserCache(ctx, id)
+	s.cache.invalidate(ctx, id)
 
 	return nil
 }
 
-func (s *userService) cacheUser(ctx context.Context, user *entity.User) {
-	if s.redis == nil {
-		return
-	}
-
-	key := fmt.Sprintf("user:%d", user.ID)
-	data, _ := json.Marshal(user)
-	s.redis.Set(ctx, key, data, 15*time.Minute)
-}
-
-func (s *userService) getCachedUser(ctx context.Context, id uint) *entity.User {
-	if s.redis == nil {
-		return nil
-	}
-
-	key := fmt.Sprintf("user:%d", id)
-	data, err := s.redis.Get(ctx, key).Result()
-	if err != nil {
-		return nil
-	}
-
-	var user entity.User
-	if err := json.Unmarshal([]byte(data), &user); err != nil {
-		return nil
+func (s *userService) checkEmailAvailable(ctx context.Context, email string) error {
+	_, err := s.userRepo.GetByEmail(ctx, email)
+	if err == nil {
+		return errors.NewConflictError("Email already in use")
 	}
-
-	return &user
-}
-
-func (s *userService) invalidateUserCache(ctx context.Context, id uint) {
-	if s.redis == nil {
-		return
+	if err != gorm.ErrRecordNotFound {
+		log.Error(ctx, "Error checking existing user", logger.Err(err))
+		return errors.NewInternalError("Failed to check existing user")
 	}
-
-	key := fmt.Sprintf("user:%d", id)
-	s.redis.Del(ctx, key)
+	return nil
 }
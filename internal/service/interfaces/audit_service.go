@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	response "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
+)
+
+type AuditService interface {
+	Record(ctx context.Context, entry audit.Entry) error
+	List(ctx context.Context, req *dto.ListAuditEventsRequest) (*response.AuditEventListResponse, error)
+}
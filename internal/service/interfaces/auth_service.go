@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	response "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, req *dto.LoginRequest) (*response.LoginResponse, error)
+	VerifyMFA(ctx context.Context, req *dto.VerifyMFARequest) (*response.LoginResponse, error)
+	// Authorize checks the user's current account, not their token, against
+	// roles.
+	Authorize(ctx context.Context, userID uint, roles ...string) error
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+)
+
+type EmailVerificationService interface {
+	SendVerification(ctx context.Context, user *entity.User) error
+	VerifyEmail(ctx context.Context, req *dto.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, req *dto.ResendVerificationRequest) error
+	SendEmailChange(ctx context.Context, user *entity.User) error
+	ConfirmEmailChange(ctx context.Context, req *dto.ConfirmEmailChangeRequest) error
+}
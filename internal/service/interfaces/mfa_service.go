@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	response "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
+)
+
+type MFAService interface {
+	EnrollTOTP(ctx context.Context, userID uint) (*response.TOTPEnrollmentResponse, error)
+	ConfirmTOTP(ctx context.Context, userID uint, req *dto.TOTPCodeRequest) (*response.RecoveryCodesResponse, error)
+	DisableTOTP(ctx context.Context, userID uint, req *dto.TOTPCodeRequest) error
+	RegenerateRecoveryCodes(ctx context.Context, userID uint, req *dto.TOTPCodeRequest) (*response.RecoveryCodesResponse, error)
+	Reset(ctx context.Context, userID uint) error
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	response "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
+)
+
+// UserAdminService holds operator actions run from the command line rather
+// than through the API.
+type UserAdminService interface {
+	CreateAdmin(ctx context.Context, req *dto.CreateUserRequest) (*response.UserResponse, error)
+	SetPassword(ctx context.Context, req *dto.SetPasswordRequest) error
+}
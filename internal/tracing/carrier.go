@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc/metadata"
+)
+
+// HeaderCarrier adapts fasthttp request headers for trace propagation.
+type HeaderCarrier struct {
+	Header *fasthttp.RequestHeader
+}
+
+func (c HeaderCarrier) Get(key string) string {
+	return string(c.Header.Peek(key))
+}
+
+func (c HeaderCarrier) Set(key, value string) {
+	c.Header.Set(key, value)
+}
+
+func (c HeaderCarrier) Keys() []string {
+	var keys []string
+	c.Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
+
+// MetadataCarrier adapts gRPC metadata for trace propagation.
+type MetadataCarrier metadata.MD
+
+func (c MetadataCarrier) Get(key string) string {
+	values := metadata.MD(c).Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// GormPlugin starts a client span for every GORM statement, as a child of
+// the span in the statement's context.
+type GormPlugin struct{}
+
+func (GormPlugin) Name() string {
+	return "tracing"
+}
+
+func (GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("tracing:before_create", before("create")),
+		cb.Create().After("gorm:create").Register("tracing:after_create", after),
+		cb.Query().Before("gorm:query").Register("tracing:before_query", before("query")),
+		cb.Query().After("gorm:query").Register("tracing:after_query", after),
+		cb.Update().Before("gorm:update").Register("tracing:before_update", before("update")),
+		cb.Update().After("gorm:update").Register("tracing:after_update", after),
+		cb.Delete().Before("gorm:delete").Register("tracing:before_delete", before("delete")),
+		cb.Delete().After("gorm:delete").Register("tracing:after_delete", after),
+		cb.Row().Before("gorm:row").Register("tracing:before_row", before("row")),
+		cb.Row().After("gorm:row").Register("tracing:after_row", after),
+		cb.Raw().Before("gorm:raw").Register("tracing:before_raw", before("raw")),
+		cb.Raw().After("gorm:raw").Register("tracing:after_raw", after),
+	)
+}
+
+func before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := Tracer().Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemKey.String(db.Dialector.Name()),
+				semconv.DBOperationName(operation),
+			),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBCollectionName(db.Statement.Table),
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
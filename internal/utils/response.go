@@ -2,19 +2,26 @@ package utils
 
 import (
 	"encoding/xml"
+	"net/http"
+	"time"
 
 	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
 	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/requestid"
 
 	"github.com/gofiber/fiber/v2"
 )
 
+var log = logger.Named("http")
+
 type BaseResponse struct {
 	Status  string      `json:"status" xml:"status"`
 	Code    int         `json:"code" xml:"code"`
 	Message string      `json:"message" xml:"message"`
 	Data    interface{} `json:"data,omitempty" xml:"data,omitempty"`
 	Error   interface{} `json:"error,omitempty" xml:"error,omitempty"`
+	// RequestID is set on errors so clients can quote it in bug reports
+	RequestID string `json:"request_id,omitempty" xml:"request_id,omitempty"`
 }
 
 func SendSuccess(c *fiber.Ctx, data interface{}) error {
@@ -27,29 +34,51 @@ func SendSuccess(c *fiber.Ctx, data interface{}) error {
 	return sendResponse(c, 200, response)
 }
 
+// SendUnavailable responds 503 with data, for health endpoints whose body
+// explains which dependency failed.
+func SendUnavailable(c *fiber.Ctx, message string, data interface{}) error {
+	response := BaseResponse{
+		Status:  "error",
+		Code:    fiber.StatusServiceUnavailable,
+		Message: message,
+		Data:    data,
+	}
+	return sendResponse(c, fiber.StatusServiceUnavailable, response)
+}
+
 func SendError(c *fiber.Ctx, err error) error {
 	var response BaseResponse
 
 	switch e := err.(type) {
 	case *errors.AppError:
 		response = BaseResponse{
-			Status:  "error",
-			Code:    e.Code,
-			Message: e.Message,
-			Error:   e.Details,
+			Status:    "error",
+			Code:      e.Code,
+			Message:   e.Message,
+			Error:     e.Details,
+			RequestID: requestid.FromContext(c.UserContext()),
 		}
 		return sendResponse(c, getHTTPStatus(e.Code), response)
 	default:
-		logger.Error("Unexpected error: ", err)
+		log.Error(c.UserContext(), "Unexpected error", logger.Err(err))
 		response = BaseResponse{
-			Status:  "error",
-			Code:    500,
-			Message: "Internal server error",
+			Status:    "error",
+			Code:      500,
+			Message:   "Internal server error",
+			RequestID: requestid.FromContext(c.UserContext()),
 		}
 		return sendResponse(c, 500, response)
 	}
 }
 
+// SetLastModified sets the Last-Modified header; zero times are ignored.
+func SetLastModified(c *fiber.Ctx, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	c.Set(fiber.HeaderLastModified, t.UTC().Format(http.TimeFormat))
+}
+
 func sendResponse(c *fiber.Ctx, statusCode int, response BaseResponse) error {
 	acceptHeader := c.Get("Accept")
 
@@ -69,6 +98,14 @@ func getHTTPStatus(code int) int {
 		return 422 // Unprocessable Entity for business logic errors
 	case 401:
 		return 401
+	case 403:
+		return 403
+	case 409:
+		return 409
+	case 429:
+		return 429
+	case 503:
+		return 503
 	case 400:
 		return 400
 	default:
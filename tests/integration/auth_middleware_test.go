@@ -0,0 +1,89 @@
+package integration
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config/configtest"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuth_UsesInjectedJWTConfig(t *testing.T) {
+	logger.Init("silent")
+	ours := configtest.New()
+	theirs := configtest.New(func(c *config.Config) {
+		c.JWT.Secret = "another-secret-0123456789abcdefghij"
+	})
+
+	newApp := func(cfg config.JWTConfig) *fiber.App {
+		app := fiber.New()
+		app.Get("/", middleware.Auth(cfg), func(c *fiber.Ctx) error { return c.SendStatus(204) })
+		return app
+	}
+
+	token, _, err := auth.GenerateToken(ours.JWT.Secret.Value(), auth.Claims{UserID: 1, Email: "jane@example.com"}, time.Minute)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		app    *fiber.App
+		status int
+	}{
+		{newApp(ours.JWT), 204},
+		{newApp(theirs.JWT), 401},
+	} {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := tc.app.Test(req)
+		require.NoError(t, err)
+		assert.Equal(t, tc.status, resp.StatusCode)
+	}
+}
+
+// roleStore is an Authorizer over in-memory roles.
+type roleStore map[uint]string
+
+func (r roleStore) Authorize(ctx context.Context, userID uint, roles ...string) error {
+	for _, role := range roles {
+		if r[userID] == role {
+			return nil
+		}
+	}
+	return errors.NewForbiddenError("Forbidden")
+}
+
+func TestRequireRole_ChecksCurrentRoleNotToken(t *testing.T) {
+	logger.Init("silent")
+	cfg := configtest.New()
+	roles := roleStore{1: entity.RoleAdmin}
+
+	app := fiber.New()
+	app.Get("/admin", middleware.Auth(cfg.JWT), middleware.RequireRole(roles, entity.RoleAdmin), func(c *fiber.Ctx) error {
+		return c.SendStatus(204)
+	})
+
+	token, _, err := auth.GenerateToken(cfg.JWT.Secret.Value(), auth.Claims{UserID: 1, Role: entity.RoleAdmin, Purpose: auth.PurposeAccess}, time.Minute)
+	require.NoError(t, err)
+	request := func() int {
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, 204, request())
+	// Demoted while the token, still claiming admin, is valid
+	roles[1] = entity.RoleUser
+	assert.Equal(t, 403, request())
+}
@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config/configtest"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCORSApp(t *testing.T, mutate func(c *config.CORSConfig)) (*fiber.App, *middleware.CORSOrigins) {
+	t.Helper()
+	cfg := configtest.New(func(c *config.Config) { mutate(&c.CORS) })
+	require.NoError(t, config.Validate(cfg))
+
+	origins := middleware.NewCORSOrigins(cfg.CORS.AllowOrigins)
+	app := fiber.New()
+	app.Use(middleware.CORS(cfg.CORS, origins))
+	app.Get("/users", func(c *fiber.Ctx) error { return c.SendStatus(204) })
+	return app, origins
+}
+
+func corsRequest(t *testing.T, app *fiber.App, method, origin string) (string, *http.Response) {
+	t.Helper()
+	req := httptest.NewRequest(method, "/users", nil)
+	req.Header.Set("Origin", origin)
+	if method == "OPTIONS" {
+		req.Header.Set("Access-Control-Request-Method", "GET")
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	return resp.Header.Get("Access-Control-Allow-Origin"), resp
+}
+
+func TestCORS_MatchesOriginPatterns(t *testing.T) {
+	app, _ := newCORSApp(t, func(c *config.CORSConfig) {
+		c.AllowOrigins = []string{"https://app.example.com", "https://*.example.org"}
+		c.AllowCredentials = true
+		c.MaxAge = 600
+	})
+
+	for origin, allowed := range map[string]bool{
+		"https://app.example.com":      true,
+		"https://APP.example.com":      true,
+		"https://a.b.example.org":      true,
+		"https://example.org":          false,
+		"http://app.example.org":       false,
+		"https://evil.com":             false,
+		"https://app.example.com.evil": false,
+	} {
+		allowOrigin, _ := corsRequest(t, app, "GET", origin)
+		if allowed {
+			assert.NotEmpty(t, allowOrigin, origin)
+		} else {
+			assert.Empty(t, allowOrigin, origin)
+		}
+	}
+
+	allowOrigin, resp := corsRequest(t, app, "OPTIONS", "https://shop.example.org")
+	assert.Equal(t, "https://shop.example.org", allowOrigin)
+	assert.Equal(t, 204, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "600", resp.Header.Get("Access-Control-Max-Age"))
+	assert.Contains(t, resp.Header.Get("Access-Control-Allow-Headers"), "Authorization")
+}
+
+func TestCORS_OriginsCanChangeWhileServing(t *testing.T) {
+	app, origins := newCORSApp(t, func(c *config.CORSConfig) {
+		c.AllowOrigins = []string{"https://old.example.com"}
+	})
+
+	allowOrigin, resp := corsRequest(t, app, "GET", "https://new.example.com")
+	assert.Empty(t, allowOrigin)
+	assert.Contains(t, resp.Header.Get("Vary"), "Origin")
+
+	origins.Set([]string{"https://new.example.com"})
+	allowOrigin, _ = corsRequest(t, app, "GET", "https://new.example.com")
+	assert.Equal(t, "https://new.example.com", allowOrigin)
+	allowOrigin, _ = corsRequest(t, app, "GET", "https://old.example.com")
+	assert.Empty(t, allowOrigin)
+}
@@ -0,0 +1,103 @@
+package integration
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config/configtest"
+	grpcserver "github.com/faizalnurrozi/go-starter-kit/internal/grpc"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	pb "github.com/faizalnurrozi/go-starter-kit/proto/user"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func freePort(t *testing.T) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+	return strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+}
+
+// withBearer returns ctx carrying an access token for user 1 in the
+// "authorization" metadata.
+func withBearer(t *testing.T, ctx context.Context, cfg config.JWTConfig) context.Context {
+	t.Helper()
+	token, _, err := auth.GenerateToken(cfg.Secret.Value(), auth.Claims{UserID: 1, Purpose: auth.PurposeAccess}, time.Minute)
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func TestGRPCServer_MutationsNeedAuthentication(t *testing.T) {
+	logger.Init("silent")
+	port := freePort(t)
+	cfg := configtest.New(func(c *config.Config) { c.GRPC.Port = port })
+	server := grpcserver.NewServer(cfg.GRPC, cfg.JWT, nil, &dummyUserService{})
+	require.NoError(t, server.Listen())
+	go server.Serve()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer server.Stop(ctx)
+
+	conn, err := grpc.NewClient("127.0.0.1:"+port, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewUserServiceClient(conn)
+
+	email := "attacker@example.com"
+	_, err = client.UpdateUser(ctx, &pb.UpdateUserRequest{Id: 1, Email: &email})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.CreateUser(ctx, &pb.CreateUserRequest{Name: "Jane", Email: "jane@example.com", Password: "secret123"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = client.DeleteUser(ctx, &pb.DeleteUserRequest{Id: 1})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = client.CreateUser(withBearer(t, ctx, cfg.JWT), &pb.CreateUserRequest{Name: "Jane", Email: "jane@example.com", Password: "secret123"})
+	assert.NoError(t, err)
+}
+
+func TestGRPCServer_HealthAndGracefulStop(t *testing.T) {
+	logger.Init("silent")
+	port := freePort(t)
+	cfg := configtest.New(func(c *config.Config) { c.GRPC.Port = port })
+	server := grpcserver.NewServer(cfg.GRPC, cfg.JWT, nil, &dummyUserService{})
+
+	require.NoError(t, server.Listen())
+	served := make(chan error, 1)
+	go func() { served <- server.Serve() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, server.Check(ctx))
+
+	conn, err := grpc.NewClient("127.0.0.1:"+port, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "user.UserService"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	server.Drain()
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	assert.NoError(t, server.Stop(ctx))
+	assert.NoError(t, <-served)
+	assert.Error(t, server.Check(ctx))
+}
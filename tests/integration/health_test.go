@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/handler"
+	"github.com/faizalnurrozi/go-starter-kit/internal/health"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealth_LivenessAndReadiness(t *testing.T) {
+	logger.Init("silent")
+	dbErr := errors.New("connection refused")
+	checker := health.NewChecker(config.HealthConfig{Timeout: 1000})
+	checker.Register("database", func(ctx context.Context) error { return dbErr })
+
+	app := fiber.New()
+	healthHandler := handler.NewHealthHandler(checker, cache.NewNoop())
+	app.Get("/livez", healthHandler.Live)
+	app.Get("/readyz", healthHandler.Ready)
+
+	// A failing dependency does not affect liveness
+	resp, err := app.Test(httptest.NewRequest("GET", "/livez", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/readyz", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+
+	var body struct {
+		Data health.Report `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, health.StatusDown, body.Data.Status)
+	assert.Equal(t, "connection refused", body.Data.Checks["database"].Error)
+}
+
+func TestHealth_ReadyUntilShutdown(t *testing.T) {
+	checker := health.NewChecker(config.HealthConfig{Timeout: 1000})
+	checker.Register("database", func(ctx context.Context) error { return nil })
+
+	app := fiber.New()
+	app.Get("/readyz", handler.NewHealthHandler(checker, cache.NewNoop()).Ready)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	checker.Shutdown()
+	resp, err = app.Test(httptest.NewRequest("GET", "/readyz", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 503, resp.StatusCode)
+}
+
+func TestHealth_NotReadyDuringShutdownDelay(t *testing.T) {
+	checker := health.NewChecker(config.HealthConfig{Timeout: 1000})
+	checker.Register("database", func(ctx context.Context) error { return nil })
+
+	app := fiber.New()
+	app.Get("/readyz", handler.NewHealthHandler(checker, cache.NewNoop()).Ready)
+
+	drained := make(chan error, 1)
+	go func() { drained <- checker.Drain(context.Background(), 300*time.Millisecond) }()
+
+	// Probes see the failure while the servers are still up
+	assert.Eventually(t, func() bool {
+		resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+		return err == nil && resp.StatusCode == 503
+	}, 200*time.Millisecond, 10*time.Millisecond)
+	select {
+	case <-drained:
+		t.Fatal("Drain returned before the delay")
+	default:
+	}
+	assert.NoError(t, <-drained)
+}
+
+func TestHealth_DrainStopsAtDeadline(t *testing.T) {
+	checker := health.NewChecker(config.HealthConfig{Timeout: 1000})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.ErrorIs(t, checker.Drain(ctx, time.Minute), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	res "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
+	"github.com/faizalnurrozi/go-starter-kit/internal/handler"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type readOnlyUserService struct {
+	dummyUserService
+	listCalls int
+}
+
+var testUserUpdatedAt = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func (s *readOnlyUserService) GetByID(ctx context.Context, id uint) (*res.UserResponse, error) {
+	return &res.UserResponse{ID: id, Name: "John Doe", UpdatedAt: testUserUpdatedAt}, nil
+}
+
+func (s *readOnlyUserService) GetAll(ctx context.Context, limit int, offset int) ([]*res.UserResponse, error) {
+	s.listCalls++
+	return []*res.UserResponse{{ID: 1, Name: "John Doe", UpdatedAt: testUserUpdatedAt}}, nil
+}
+
+func TestUserHandler_GetByID_ConditionalGet(t *testing.T) {
+	app := fiber.New()
+	userHandler := handler.NewUserHandler(&readOnlyUserService{})
+	app.Get("/users/:id", middleware.HTTPCache("private, no-cache"), middleware.ValidateParams(), userHandler.GetByID)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "private, no-cache", resp.Header.Get("Cache-Control"))
+	assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", resp.Header.Get("Last-Modified"))
+	etag := resp.Header.Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]+"$`, etag)
+
+	httpReq := httptest.NewRequest("GET", "/users/1", nil)
+	httpReq.Header.Set("If-None-Match", etag)
+	resp, err = app.Test(httpReq)
+	assert.NoError(t, err)
+	assert.Equal(t, 304, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Empty(t, body)
+
+	httpReq = httptest.NewRequest("GET", "/users/1", nil)
+	httpReq.Header.Set("If-Modified-Since", "Wed, 01 May 2024 12:00:00 GMT")
+	resp, err = app.Test(httpReq)
+	assert.NoError(t, err)
+	assert.Equal(t, 304, resp.StatusCode)
+
+	httpReq = httptest.NewRequest("GET", "/users/1", nil)
+	httpReq.Header.Set("If-None-Match", `W/"stale"`)
+	resp, err = app.Test(httpReq)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestUserHandler_GetAll_SharedResponseCache(t *testing.T) {
+	app := fiber.New()
+	store := cache.NewLRU(100)
+	userService := &readOnlyUserService{}
+	userHandler := handler.NewUserHandler(userService)
+	app.Get("/users", middleware.ResponseCache(store, "users:list", time.Minute), userHandler.GetAll)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users?limit=10&offset=0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "MISS", resp.Header.Get("X-Cache"))
+
+	// Same query in a different order is served from the cache
+	resp, err = app.Test(httptest.NewRequest("GET", "/users?offset=0&limit=10", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+	assert.Equal(t, "HIT", resp.Header.Get("X-Cache"))
+	assert.Equal(t, "Wed, 01 May 2024 12:00:00 GMT", resp.Header.Get("Last-Modified"))
+	assert.Equal(t, 1, userService.listCalls)
+
+	assert.NoError(t, cache.NewGroup(store, "users:list").Invalidate(context.Background()))
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/users?limit=10&offset=0", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "MISS", resp.Header.Get("X-Cache"))
+	assert.Equal(t, 2, userService.listCalls)
+}
@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config/configtest"
+	res "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	grpcserver "github.com/faizalnurrozi/go-starter-kit/internal/grpc"
+	"github.com/faizalnurrozi/go-starter-kit/internal/handler"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/metrics"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+	pb "github.com/faizalnurrozi/go-starter-kit/proto/user"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func TestMetrics_LabelsByRouteTemplate(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.Metrics())
+	userHandler := handler.NewUserHandler(&readOnlyUserService{})
+	app.Get("/users/:id", middleware.ValidateParams(), userHandler.GetByID)
+
+	series := metrics.HTTPRequests.WithLabelValues("GET", "/users/:id", "200")
+	before := testutil.ToFloat64(series)
+
+	for _, path := range []string{"/users/1", "/users/2", "/missing"} {
+		_, err := app.Test(httptest.NewRequest("GET", path, nil))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, before+2, testutil.ToFloat64(series))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues("GET", "unmatched", "404")))
+
+	// The exposition includes runtime metrics alongside the HTTP series
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	assert.Contains(t, string(body), `http_requests_total{method="GET",route="/users/:id",status="200"}`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+// missingUserService finds no users.
+type missingUserService struct {
+	dummyUserService
+}
+
+func (s *missingUserService) GetByID(ctx context.Context, id uint) (*res.UserResponse, error) {
+	return nil, errors.NewNotFoundError("User")
+}
+
+func TestMetrics_GRPCCodesFromAppErrors(t *testing.T) {
+	logger.Init("silent")
+	port := freePort(t)
+	cfg := configtest.New(func(c *config.Config) { c.GRPC.Port = port })
+	server := grpcserver.NewServer(cfg.GRPC, cfg.JWT, nil, &missingUserService{})
+	require.NoError(t, server.Listen())
+	go server.Serve()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer server.Stop(ctx)
+
+	conn, err := grpc.NewClient("127.0.0.1:"+port, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	series := metrics.GRPCRequests.WithLabelValues("/user.UserService/GetUser", "NotFound")
+	before := testutil.ToFloat64(series)
+
+	_, err = pb.NewUserServiceClient(conn).GetUser(ctx, &pb.GetUserRequest{Id: 42})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "User not found", status.Convert(err).Message())
+	assert.Equal(t, before+1, testutil.ToFloat64(series))
+}
@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config/configtest"
+	grpcserver "github.com/faizalnurrozi/go-starter-kit/internal/grpc"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+	"github.com/faizalnurrozi/go-starter-kit/internal/ratelimit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
+	pb "github.com/faizalnurrozi/go-starter-kit/proto/user"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestLimiter(t *testing.T, mutate func(c *config.RateLimitConfig)) *ratelimit.Limiter {
+	t.Helper()
+	cfg := configtest.New(func(c *config.Config) {
+		c.RateLimit.Enabled = true
+		c.RateLimit.Driver = "memory"
+		mutate(&c.RateLimit)
+	})
+	require.NoError(t, config.Validate(cfg))
+
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), cfg.RateLimit)
+	require.NoError(t, err)
+	return limiter
+}
+
+func TestRateLimit_HTTPHeadersAndRejection(t *testing.T) {
+	logger.Init("silent")
+	limiter := newTestLimiter(t, func(c *config.RateLimitConfig) {
+		c.Routes = []string{"POST /login 2/1m", "/health off"}
+	})
+
+	app := fiber.New()
+	app.Use(middleware.RateLimit(limiter))
+	app.Post("/login", func(c *fiber.Ctx) error { return c.SendStatus(204) })
+	app.Get("/health", func(c *fiber.Ctx) error { return c.SendStatus(204) })
+
+	for i, remaining := range []string{"1", "0"} {
+		resp, err := app.Test(httptest.NewRequest("POST", "/login", nil))
+		require.NoError(t, err)
+		assert.Equal(t, 204, resp.StatusCode, "request %d", i)
+		assert.Equal(t, "2", resp.Header.Get("RateLimit-Limit"))
+		assert.Equal(t, remaining, resp.Header.Get("RateLimit-Remaining"))
+		assert.Empty(t, resp.Header.Get("Retry-After"))
+	}
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/login", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 429, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+	assert.Equal(t, "2;w=60", resp.Header.Get("RateLimit-Policy"))
+
+	var body utils.BaseResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 429, body.Code)
+
+	// Exempt routes are neither limited nor labelled
+	resp, err = app.Test(httptest.NewRequest("GET", "/health", nil))
+	require.NoError(t, err)
+	assert.Equal(t, 204, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("RateLimit-Limit"))
+}
+
+func TestRateLimit_RandomAPIKeysStillHitLoginLimit(t *testing.T) {
+	logger.Init("silent")
+	limiter := newTestLimiter(t, func(c *config.RateLimitConfig) {
+		c.KeyBy = []string{"api_key", "user", "ip"}
+		c.Routes = []string{"POST /login 5/1m"}
+	})
+
+	app := fiber.New()
+	app.Use(middleware.RateLimit(limiter))
+	app.Post("/login", func(c *fiber.Ctx) error { return c.SendStatus(204) })
+
+	statuses := make([]int, 0, 8)
+	for i := 0; i < 8; i++ {
+		req := httptest.NewRequest("POST", "/login", nil)
+		req.Header.Set("X-API-Key", fmt.Sprintf("random-key-%d", i))
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		statuses = append(statuses, resp.StatusCode)
+	}
+	assert.Equal(t, []int{204, 204, 204, 204, 204, 429, 429, 429}, statuses)
+}
+
+func TestRateLimit_ForwardedIPOnlyFromTrustedProxies(t *testing.T) {
+	logger.Init("silent")
+
+	// app.Test connects from 0.0.0.0, standing in for the proxy
+	loginStatuses := func(trusted string) []int {
+		cfg := configtest.New(func(c *config.Config) {
+			c.Server.ProxyHeader = "X-Real-IP"
+			c.Server.TrustedProxies = []string{trusted}
+		})
+		require.NoError(t, config.Validate(cfg))
+		limiter := newTestLimiter(t, func(c *config.RateLimitConfig) {
+			c.Routes = []string{"POST /login 1/1m"}
+		})
+
+		app := fiber.New(middleware.TrustedProxies(fiber.Config{}, cfg.Server))
+		app.Use(middleware.RateLimit(limiter))
+		app.Post("/login", func(c *fiber.Ctx) error { return c.SendStatus(204) })
+
+		var statuses []int
+		for _, client := range []string{"203.0.113.1", "203.0.113.2"} {
+			req := httptest.NewRequest("POST", "/login", nil)
+			req.Header.Set("X-Real-IP", client)
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			statuses = append(statuses, resp.StatusCode)
+		}
+		return statuses
+	}
+
+	// Each forwarded client has its own budget behind a trusted proxy
+	assert.Equal(t, []int{204, 204}, loginStatuses("0.0.0.0/32"))
+	// From anywhere else the header is ignored and cannot mint new budgets
+	assert.Equal(t, []int{204, 429}, loginStatuses("10.0.0.0/8"))
+}
+
+func TestRateLimit_GRPCResourceExhausted(t *testing.T) {
+	logger.Init("silent")
+	port := freePort(t)
+	cfg := configtest.New(func(c *config.Config) { c.GRPC.Port = port })
+	limiter := newTestLimiter(t, func(c *config.RateLimitConfig) {
+		c.Methods = []string{"/user.UserService/CreateUser 1/1m"}
+	})
+
+	server := grpcserver.NewServer(cfg.GRPC, cfg.JWT, limiter, &dummyUserService{})
+	require.NoError(t, server.Listen())
+	go server.Serve()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	defer server.Stop(ctx)
+
+	conn, err := grpc.NewClient("127.0.0.1:"+port, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := pb.NewUserServiceClient(conn)
+	ctx = withBearer(t, ctx, cfg.JWT)
+
+	var header metadata.MD
+	_, err = client.CreateUser(ctx, &pb.CreateUserRequest{Name: "Jane", Email: "jane@example.com", Password: "secret123"}, grpc.Header(&header))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0"}, header.Get("ratelimit-remaining"))
+
+	_, err = client.CreateUser(ctx, &pb.CreateUserRequest{Name: "Jane", Email: "jane@example.com", Password: "secret123"}, grpc.Header(&header))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	assert.NotEmpty(t, header.Get("retry-after"))
+}
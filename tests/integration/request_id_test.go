@@ -0,0 +1,52 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+	"github.com/faizalnurrozi/go-starter-kit/internal/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID_EchoedInHeaderAndErrors(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return utils.SendError(c, errors.NewConflictError("Email already in use"))
+	})
+
+	httpReq := httptest.NewRequest("GET", "/fail", nil)
+	httpReq.Header.Set("X-Request-ID", "req-123")
+	resp, err := app.Test(httpReq)
+	assert.NoError(t, err)
+	assert.Equal(t, "req-123", resp.Header.Get("X-Request-ID"))
+
+	var body utils.BaseResponse
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "req-123", body.RequestID)
+}
+
+func TestRequestID_GeneratedWhenMissingOrInvalid(t *testing.T) {
+	app := fiber.New()
+	app.Use(middleware.RequestID())
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(204) })
+
+	for _, supplied := range []string{"", "has spaces", strings.Repeat("a", 129)} {
+		httpReq := httptest.NewRequest("GET", "/", nil)
+		if supplied != "" {
+			httpReq.Header.Set("X-Request-ID", supplied)
+		}
+		resp, err := app.Test(httpReq)
+		assert.NoError(t, err)
+
+		id := resp.Header.Get("X-Request-ID")
+		assert.Len(t, id, 36, "supplied %q", supplied)
+		assert.NotEqual(t, supplied, id)
+	}
+}
@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/handler"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/middleware"
+	"github.com/faizalnurrozi/go-starter-kit/internal/tracing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestTracing_ContinuesIncomingTrace(t *testing.T) {
+	_, err := tracing.Init(context.Background(), config.TracingConfig{Exporter: "none"})
+	assert.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	logger.Init("info")
+	var logs bytes.Buffer
+	logger.SetOutput(&logs)
+	t.Cleanup(func() { logger.Init("silent") })
+
+	app := fiber.New()
+	app.Use(middleware.Tracing())
+	app.Use(middleware.Logger())
+	userHandler := handler.NewUserHandler(&readOnlyUserService{})
+	app.Get("/users/:id", middleware.ValidateParams(), userHandler.GetByID)
+
+	httpReq := httptest.NewRequest("GET", "/users/1", nil)
+	httpReq.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := app.Test(httpReq)
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		span := spans[0]
+		assert.Equal(t, "GET /users/:id", span.Name())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+	}
+
+	// The request log line carries the same trace
+	var entry logrus.Fields
+	assert.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry["trace_id"])
+}
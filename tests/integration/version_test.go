@@ -0,0 +1,60 @@
+package integration
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/buildinfo"
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/handler"
+	"github.com/faizalnurrozi/go-starter-kit/internal/health"
+	"github.com/faizalnurrozi/go-starter-kit/internal/metrics"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersion_ReportsBuildMetadata(t *testing.T) {
+	defer func(version, commit string) {
+		buildinfo.Version, buildinfo.Commit = version, commit
+	}(buildinfo.Version, buildinfo.Commit)
+	buildinfo.Version, buildinfo.Commit = "v1.2.3", "abc123"
+
+	app := fiber.New()
+	healthHandler := handler.NewHealthHandler(health.NewChecker(config.HealthConfig{Timeout: 1000}), cache.NewNoop())
+	app.Get("/version", healthHandler.Version)
+	app.Get("/health", healthHandler.Check)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/version", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	var body struct {
+		Data buildinfo.Info `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "v1.2.3", body.Data.Version)
+	assert.Equal(t, "abc123", body.Data.Commit)
+	assert.Equal(t, runtime.Version(), body.Data.GoVersion)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/health", nil))
+	assert.NoError(t, err)
+	var report struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	assert.Equal(t, "v1.2.3", report.Data["version"])
+	assert.Equal(t, "abc123", report.Data["commit"])
+}
+
+func TestVersion_ExportsBuildInfoMetric(t *testing.T) {
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+	assert.Contains(t, string(body), `app_build_info{build_time=`)
+	assert.Contains(t, string(body), `service="go-starter-kit"`)
+}
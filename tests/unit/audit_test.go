@@ -0,0 +1,43 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditDiff_RecordsChangedFieldsOnly(t *testing.T) {
+	before := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com", IsActive: true}
+	after := *before
+	after.Name = "Johnny"
+	after.UpdatedAt = time.Now()
+
+	changes := audit.Diff(audit.Snapshot(before), audit.Snapshot(&after))
+
+	assert.Equal(t, map[string]audit.Change{
+		"name": {Before: "John Doe", After: "Johnny"},
+	}, changes)
+}
+
+func TestAuditDiff_RedactsSecrets(t *testing.T) {
+	before := &entity.User{ID: 1, Password: "old-hash"}
+	after := &entity.User{ID: 1, Password: "new-hash", TOTPSecret: "JBSWY3DPEHPK3PXP"}
+
+	changes := audit.Diff(audit.Snapshot(before), audit.Snapshot(after))
+
+	assert.Equal(t, audit.Change{Before: audit.Redacted, After: audit.Redacted}, changes["password"])
+	assert.Equal(t, audit.Change{Before: nil, After: audit.Redacted}, changes["totp_secret"])
+}
+
+func TestAuditDiff_Deletion(t *testing.T) {
+	before := &entity.User{ID: 1, Name: "John Doe"}
+
+	changes := audit.Diff(audit.Snapshot(before), nil)
+
+	assert.Equal(t, "John Doe", changes["name"].Before)
+	assert.Nil(t, changes["name"].After)
+}
@@ -0,0 +1,274 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type MockRecoveryCodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockRecoveryCodeRepository) Replace(ctx context.Context, userID uint, codes []entity.RecoveryCode) error {
+	args := m.Called(ctx, userID, codes)
+	return args.Error(0)
+}
+
+func (m *MockRecoveryCodeRepository) Consume(ctx context.Context, userID uint, codeHash string) (bool, error) {
+	args := m.Called(ctx, userID, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRecoveryCodeRepository) CountUnused(ctx context.Context, userID uint) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID uint) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+var (
+	testJWTConfig = config.JWTConfig{Secret: "test-secret-test-secret-test-secret", Expire: 1}
+	testMFAConfig = config.MFAConfig{Issuer: "test", RequiredRoles: []string{entity.RoleAdmin}, PendingTokenExpire: 5, RecoveryCodeCount: 10, MaxFailedAttempts: 3, Lockout: 15}
+)
+
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	// Secret "12345678901234567890" from RFC 6238 appendix B, truncated to 6 digits.
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	code, err := auth.TOTPCode(secret, time.Unix(59, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, "287082", code)
+
+	code, err = auth.TOTPCode(secret, time.Unix(1111111109, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, "081804", code)
+}
+
+func TestTOTP_RejectsReplayedStep(t *testing.T) {
+	secret, err := auth.GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	now := time.Now()
+	code, _ := auth.TOTPCode(secret, now)
+
+	step, ok := auth.ValidateTOTP(secret, code, now, 0)
+	assert.True(t, ok)
+
+	_, ok = auth.ValidateTOTP(secret, code, now, step)
+	assert.False(t, ok)
+}
+
+func TestAuthService_Login_MFARequired(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	authService := serviceimpl.NewAuthService(mockRepo, new(MockRecoveryCodeRepository), testJWTConfig, testMFAConfig, config.VerificationConfig{})
+
+	ctx := context.Background()
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	enabledAt := time.Now()
+	user := &entity.User{
+		ID:            1,
+		Email:         "john@example.com",
+		Password:      string(hashed),
+		Role:          entity.RoleUser,
+		IsActive:      true,
+		TOTPSecret:    "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ",
+		TOTPEnabledAt: &enabledAt,
+	}
+	mockRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+
+	result, err := authService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.True(t, result.MFARequired)
+	assert.Empty(t, result.AccessToken)
+
+	claims, err := auth.ParseToken(testJWTConfig.Secret.Value(), result.MFAToken)
+	assert.NoError(t, err)
+	assert.Equal(t, auth.PurposeMFA, claims.Purpose)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Login_EnrollmentRequiredForRole(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	authService := serviceimpl.NewAuthService(mockRepo, new(MockRecoveryCodeRepository), testJWTConfig, testMFAConfig, config.VerificationConfig{})
+
+	ctx := context.Background()
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &entity.User{
+		ID:       2,
+		Email:    "admin@example.com",
+		Password: string(hashed),
+		Role:     entity.RoleAdmin,
+		IsActive: true,
+	}
+	mockRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+
+	result, err := authService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "password123"})
+
+	assert.NoError(t, err)
+	assert.True(t, result.MFAEnrollmentRequired)
+	assert.Empty(t, result.AccessToken)
+}
+
+func TestAuthService_VerifyMFA_TOTP(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	authService := serviceimpl.NewAuthService(mockRepo, new(MockRecoveryCodeRepository), testJWTConfig, testMFAConfig, config.VerificationConfig{})
+
+	ctx := context.Background()
+	secret, _ := auth.GenerateTOTPSecret()
+	enabledAt := time.Now()
+	user := &entity.User{ID: 3, Email: "jane@example.com", Role: entity.RoleUser, IsActive: true, TOTPSecret: secret, TOTPEnabledAt: &enabledAt}
+	mfaToken, _, _ := auth.GenerateToken(testJWTConfig.Secret.Value(), auth.Claims{UserID: user.ID, Purpose: auth.PurposeMFA}, time.Minute)
+	code, _ := auth.TOTPCode(secret, time.Now())
+
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRepo.On("UpdateTOTPLastStep", ctx, user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+
+	result, err := authService.VerifyMFA(ctx, &dto.VerifyMFARequest{MFAToken: mfaToken, Code: code})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.AccessToken)
+	assert.False(t, result.MFARequired)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyMFA_RecoveryCodeIsSingleUse(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	recoveryRepo := new(MockRecoveryCodeRepository)
+	authService := serviceimpl.NewAuthService(mockRepo, recoveryRepo, testJWTConfig, testMFAConfig, config.VerificationConfig{})
+
+	ctx := context.Background()
+	enabledAt := time.Now()
+	user := &entity.User{ID: 3, Role: entity.RoleUser, IsActive: true, TOTPSecret: "secret", TOTPEnabledAt: &enabledAt}
+	mfaToken, _, _ := auth.GenerateToken(testJWTConfig.Secret.Value(), auth.Claims{UserID: user.ID, Purpose: auth.PurposeMFA}, time.Minute)
+	req := &dto.VerifyMFARequest{MFAToken: mfaToken, RecoveryCode: "abcd-efgh"}
+
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	recoveryRepo.On("Consume", ctx, user.ID, auth.HashRecoveryCode(req.RecoveryCode)).Return(true, nil).Once()
+	recoveryRepo.On("Consume", ctx, user.ID, auth.HashRecoveryCode(req.RecoveryCode)).Return(false, nil).Once()
+	mockRepo.On("RecordMFAFailure", ctx, user.ID).Return(1, nil)
+
+	result, err := authService.VerifyMFA(ctx, req)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.AccessToken)
+
+	_, err = authService.VerifyMFA(ctx, req)
+	assert.Equal(t, http.StatusUnauthorized, err.(*errors.AppError).Code)
+	mockRepo.AssertExpectations(t)
+	recoveryRepo.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyMFA_LocksAfterFailedAttempts(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	authService := serviceimpl.NewAuthService(mockRepo, new(MockRecoveryCodeRepository), testJWTConfig, testMFAConfig, config.VerificationConfig{})
+
+	ctx := context.Background()
+	secret, _ := auth.GenerateTOTPSecret()
+	enabledAt := time.Now()
+	user := &entity.User{ID: 3, Role: entity.RoleUser, IsActive: true, TOTPSecret: secret, TOTPEnabledAt: &enabledAt}
+	mfaToken, _, _ := auth.GenerateToken(testJWTConfig.Secret.Value(), auth.Claims{UserID: user.ID, Purpose: auth.PurposeMFA}, time.Minute)
+	code, _ := auth.TOTPCode(secret, time.Now())
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRepo.On("RecordMFAFailure", ctx, user.ID).Return(1, nil).Once()
+	mockRepo.On("RecordMFAFailure", ctx, user.ID).Return(2, nil).Once()
+	mockRepo.On("RecordMFAFailure", ctx, user.ID).Return(3, nil).Once()
+	mockRepo.On("LockMFA", ctx, user.ID, mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		until := args.Get(2).(time.Time)
+		user.MFALockedUntil = &until
+	}).Return(nil)
+
+	statuses := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		_, err := authService.VerifyMFA(ctx, &dto.VerifyMFARequest{MFAToken: mfaToken, Code: wrong})
+		statuses = append(statuses, err.(*errors.AppError).Code)
+	}
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, statuses)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), *user.MFALockedUntil, time.Minute)
+
+	// While locked even the right code is refused without being checked
+	_, err := authService.VerifyMFA(ctx, &dto.VerifyMFARequest{MFAToken: mfaToken, Code: code})
+	assert.Equal(t, http.StatusTooManyRequests, err.(*errors.AppError).Code)
+	mockRepo.AssertNotCalled(t, "UpdateTOTPLastStep", ctx, user.ID, mock.Anything)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthService_VerifyMFA_SuccessResetsFailures(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	authService := serviceimpl.NewAuthService(mockRepo, new(MockRecoveryCodeRepository), testJWTConfig, testMFAConfig, config.VerificationConfig{})
+
+	ctx := context.Background()
+	secret, _ := auth.GenerateTOTPSecret()
+	enabledAt := time.Now()
+	lockedUntil := time.Now().Add(-time.Minute)
+	user := &entity.User{ID: 3, Role: entity.RoleUser, IsActive: true, TOTPSecret: secret, TOTPEnabledAt: &enabledAt, MFAFailedAttempts: 2, MFALockedUntil: &lockedUntil}
+	mfaToken, _, _ := auth.GenerateToken(testJWTConfig.Secret.Value(), auth.Claims{UserID: user.ID, Purpose: auth.PurposeMFA}, time.Minute)
+	code, _ := auth.TOTPCode(secret, time.Now())
+
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRepo.On("UpdateTOTPLastStep", ctx, user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+	mockRepo.On("ResetMFAFailures", ctx, user.ID).Return(nil)
+
+	result, err := authService.VerifyMFA(ctx, &dto.VerifyMFARequest{MFAToken: mfaToken, Code: code})
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, result.AccessToken)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestAuthService_Authorize_UsesCurrentAccount(t *testing.T) {
+	logger.Init("silent")
+	enabledAt := time.Now()
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		user   *entity.User
+		status int
+	}{
+		"admin with second factor": {&entity.User{ID: 1, Role: entity.RoleAdmin, IsActive: true, TOTPSecret: "s", TOTPEnabledAt: &enabledAt}, 0},
+		"demoted admin":            {&entity.User{ID: 1, Role: entity.RoleUser, IsActive: true}, http.StatusForbidden},
+		"admin after MFA reset":    {&entity.User{ID: 1, Role: entity.RoleAdmin, IsActive: true}, http.StatusForbidden},
+		"disabled admin":           {&entity.User{ID: 1, Role: entity.RoleAdmin, TOTPSecret: "s", TOTPEnabledAt: &enabledAt}, http.StatusForbidden},
+	} {
+		mockRepo := new(MockUserRepository)
+		authService := serviceimpl.NewAuthService(mockRepo, new(MockRecoveryCodeRepository), testJWTConfig, testMFAConfig, config.VerificationConfig{})
+		mockRepo.On("GetByID", ctx, uint(1)).Return(tc.user, nil)
+
+		err := authService.Authorize(ctx, 1, entity.RoleAdmin)
+		if tc.status == 0 {
+			assert.NoError(t, err, name)
+			continue
+		}
+		appErr, ok := err.(*errors.AppError)
+		if assert.True(t, ok, name) {
+			assert.Equal(t, tc.status, appErr.Code, name)
+		}
+	}
+}
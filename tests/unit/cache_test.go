@@ -0,0 +1,380 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var testCacheConfig = config.CacheConfig{
+	TTL:         900,
+	NegativeTTL: 30,
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewLRU(2)
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	assert.NoError(t, c.Set(ctx, "b", []byte("2"), 0))
+
+	// Touch "a" so "b" becomes the eviction candidate
+	_, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+
+	_, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, cache.ErrMiss)
+
+	values, err := c.MultiGet(ctx, "a", "b", "c")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"a": []byte("1"), "c": []byte("3")}, values)
+}
+
+func TestLRU_ExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewLRU(10)
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("1"), 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := c.Get(ctx, "a")
+	assert.ErrorIs(t, err, cache.ErrMiss)
+}
+
+func TestTyped_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	users := cache.NewTyped(cache.NewLRU(10), cache.JSONCodec[entity.User]())
+
+	_, ok, err := users.Get(ctx, "user:1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, users.Set(ctx, "user:1", entity.User{ID: 1, Name: "John Doe"}, time.Minute))
+
+	user, ok, err := users.Get(ctx, "user:1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "John Doe", user.Name)
+}
+
+func TestUserService_GetByID_UsesCache(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewLRU(10), testCacheConfig, nil, passthroughTransactor{}, new(MockAuditService))
+
+	ctx := context.Background()
+	user := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+	mockRepo.On("GetByID", mock.Anything, user.ID).Return(user, nil).Once()
+
+	first, err := userService.GetByID(ctx, user.ID)
+	assert.NoError(t, err)
+	second, err := userService.GetByID(ctx, user.ID)
+	assert.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 1)
+}
+
+func TestUserService_GetByID_CoalescesConcurrentMisses(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewLRU(10), testCacheConfig, nil, passthroughTransactor{}, new(MockAuditService))
+
+	ctx := context.Background()
+	user := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+	release := make(chan struct{})
+	mockRepo.On("GetByID", mock.Anything, user.ID).
+		Run(func(mock.Arguments) { <-release }).
+		Return(user, nil).Once()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := userService.GetByID(ctx, user.ID)
+			assert.NoError(t, err)
+		}()
+	}
+
+	// Give every caller time to join the in-flight load
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 1)
+}
+
+func TestUserService_GetByID_CachesNotFound(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewLRU(10), testCacheConfig, nil, passthroughTransactor{}, new(MockAuditService))
+
+	ctx := context.Background()
+	mockRepo.On("GetByID", mock.Anything, uint(404)).Return((*entity.User)(nil), gorm.ErrRecordNotFound).Once()
+
+	for i := 0; i < 2; i++ {
+		_, err := userService.GetByID(ctx, 404)
+		assert.Error(t, err)
+		assert.Equal(t, "User not found", err.Error())
+	}
+
+	mockRepo.AssertNumberOfCalls(t, "GetByID", 1)
+}
+
+func TestLoader_ServesStaleWhileRevalidating(t *testing.T) {
+	ctx := context.Background()
+	loader := cache.NewLoader(cache.NewLRU(10), cache.JSONCodec[string](), cache.LoaderOptions{
+		TTL:      time.Millisecond,
+		StaleTTL: time.Minute,
+	})
+
+	var loads atomic.Int32
+	load := func(ctx context.Context) (string, error) {
+		return fmt.Sprintf("v%d", loads.Add(1)), nil
+	}
+
+	value, err := loader.Get(ctx, "k", load)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	time.Sleep(5 * time.Millisecond)
+
+	// The stale value comes back at once and a reload starts behind it
+	value, err = loader.Get(ctx, "k", load)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	assert.Eventually(t, func() bool {
+		return loads.Load() == 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func newTieredPair(t *testing.T) (*miniredis.Miniredis, cache.Cache, cache.Cache) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	opts := cache.TieredOptions{L1Size: 100, L1TTL: time.Minute, Channel: "cache:invalidate"}
+
+	a := cache.NewTiered(redis.NewClient(&redis.Options{Addr: mr.Addr()}), opts)
+	b := cache.NewTiered(redis.NewClient(&redis.Options{Addr: mr.Addr()}), opts)
+	t.Cleanup(func() {
+		a.Close()
+		b.Close()
+	})
+
+	// Subscriptions are made in the background; wait for both
+	assert.Eventually(t, func() bool {
+		return mr.PubSubNumSub(opts.Channel)[opts.Channel] == 2
+	}, time.Second, 10*time.Millisecond)
+	return mr, a, b
+}
+
+func TestTiered_CountsHitsPerTier(t *testing.T) {
+	ctx := context.Background()
+	mr, _, b := newTieredPair(t)
+
+	// Written straight to Redis, so the first read misses L1
+	assert.NoError(t, mr.Set("k", "v"))
+
+	for i := 0; i < 2; i++ {
+		value, err := b.Get(ctx, "k")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("v"), value)
+	}
+	_, err := b.Get(ctx, "missing")
+	assert.ErrorIs(t, err, cache.ErrMiss)
+
+	stats := b.(cache.StatsProvider).Stats()
+	assert.Equal(t, cache.Stats{L1Hits: 1, L2Hits: 1, Misses: 1}, stats)
+	assert.InDelta(t, 1.0/3, stats.L1HitRate(), 0.001)
+	assert.InDelta(t, 0.5, stats.L2HitRate(), 0.001)
+}
+
+func TestTiered_DeleteInvalidatesOtherInstances(t *testing.T) {
+	ctx := context.Background()
+	_, a, b := newTieredPair(t)
+
+	assert.NoError(t, a.Set(ctx, "k", []byte("v1"), time.Minute))
+	_, err := b.Get(ctx, "k")
+	assert.NoError(t, err)
+
+	assert.NoError(t, a.Delete(ctx, "k"))
+
+	assert.Eventually(t, func() bool {
+		_, err := b.Get(ctx, "k")
+		return err == cache.ErrMiss
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestLoader_DeleteDropsRacingWrites(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewLRU(10)
+	loader := cache.NewLoader(store, cache.JSONCodec[string](), cache.LoaderOptions{
+		TTL:         time.Minute,
+		DeleteDelay: 20 * time.Millisecond,
+	})
+
+	assert.NoError(t, loader.Delete(ctx, "k"))
+
+	// A read that loaded the old row before the write caches it afterwards
+	assert.NoError(t, loader.Set(ctx, "k", "old"))
+
+	assert.Eventually(t, func() bool {
+		_, err := store.Get(ctx, "k")
+		return err == cache.ErrMiss
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestWithPrefix_NamespacesKeys(t *testing.T) {
+	ctx := context.Background()
+	store := cache.NewLRU(10)
+	c := cache.WithPrefix(store, "app:v2:")
+
+	assert.NoError(t, c.Set(ctx, "user:1", []byte("1"), 0))
+
+	_, err := store.Get(ctx, "user:1")
+	assert.ErrorIs(t, err, cache.ErrMiss)
+	_, err = store.Get(ctx, "app:v2:user:1")
+	assert.NoError(t, err)
+
+	values, err := c.MultiGet(ctx, "user:1", "user:2")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"user:1": []byte("1")}, values)
+}
+
+func TestUserService_Create_CachesWithoutCredentials(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	store := cache.NewLRU(10)
+	userService := serviceimpl.NewUserService(mockRepo, store, testCacheConfig, nil, passthroughTransactor{}, mockAudit)
+
+	ctx := context.Background()
+	req := &dto.CreateUserRequest{Name: "John Doe", Email: "john@example.com", Password: "password123"}
+	mockRepo.On("GetByEmail", ctx, req.Email).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Run(func(args mock.Arguments) {
+		args.Get(1).(*entity.User).ID = 1
+	}).Return(nil)
+	mockAudit.On("Record", ctx, mock.Anything).Return(nil)
+
+	_, err := userService.Create(ctx, req)
+	assert.NoError(t, err)
+
+	raw, err := store.Get(ctx, "user:1")
+	assert.NoError(t, err)
+
+	// Loader entries carry the codec output in "v"
+	var entry struct {
+		V []byte `json:"v"`
+	}
+	assert.NoError(t, json.Unmarshal(raw, &entry))
+	data := entry.V
+	assert.Contains(t, string(data), "john@example.com")
+	assert.NotContains(t, string(data), "password")
+	assert.NotContains(t, string(data), "deleted_at")
+}
+
+func redisTestConfig(addr, driver string) *config.Config {
+	host, port, _ := strings.Cut(addr, ":")
+	return &config.Config{
+		Redis: config.RedisConfig{Host: host, Port: port, DialTimeout: 200},
+		Cache: config.CacheConfig{Driver: driver, MemorySize: 10, Namespace: "test", KeyVersion: 1},
+	}
+}
+
+func TestNew_PingsRedisAtStartup(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	c, err := cache.New(redisTestConfig(mr.Addr(), "tiered"))
+	assert.NoError(t, err)
+	defer c.Close()
+
+	ok, err := cache.Ping(context.Background(), c)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+
+	// Stats stay reachable through the key prefix wrapper
+	_, ok = cache.StatsOf(c)
+	assert.True(t, ok)
+
+	ok, _ = cache.Ping(context.Background(), cache.NewLRU(1))
+	assert.False(t, ok)
+}
+
+func TestNew_UnreachableRedis(t *testing.T) {
+	logger.Init("silent")
+	mr := miniredis.RunT(t)
+	cfg := redisTestConfig(mr.Addr(), "redis")
+	mr.Close()
+
+	_, err := cache.New(cfg)
+	assert.Error(t, err)
+
+	cfg.Redis.FailOpen = true
+	c, err := cache.New(cfg)
+	assert.NoError(t, err)
+	defer c.Close()
+
+	ok, err := cache.Ping(context.Background(), c)
+	assert.True(t, ok)
+	assert.Error(t, err)
+}
+
+func TestNewRedisClient_ValidatesMode(t *testing.T) {
+	cfg := redisTestConfig("localhost:6379", "redis")
+
+	cfg.Redis.Mode = "sentinel"
+	_, err := cache.NewRedisClient(cfg)
+	assert.EqualError(t, err, "redis sentinel mode requires master_name")
+
+	cfg.Redis.Mode = "replicated"
+	_, err = cache.NewRedisClient(cfg)
+	assert.EqualError(t, err, "unsupported redis mode: replicated")
+
+	cfg.Redis.Mode = "cluster"
+	cfg.Redis.Addrs = []string{"redis-1:6379", "redis-2:6379"}
+	client, err := cache.NewRedisClient(cfg)
+	assert.NoError(t, err)
+	assert.IsType(t, &redis.ClusterClient{}, client)
+	client.Close()
+}
+
+func TestLoader_ReportsLookupResults(t *testing.T) {
+	ctx := context.Background()
+	var results []string
+	loader := cache.NewLoader(cache.NewLRU(10), cache.JSONCodec[string](), cache.LoaderOptions{
+		TTL:         time.Minute,
+		NegativeTTL: time.Minute,
+		OnLookup:    func(result string) { results = append(results, result) },
+	})
+
+	found := func(ctx context.Context) (string, error) { return "v", nil }
+	missing := func(ctx context.Context) (string, error) { return "", cache.ErrNotFound }
+
+	loader.Get(ctx, "a", found)
+	loader.Get(ctx, "a", found)
+	loader.Get(ctx, "b", missing)
+	loader.Get(ctx, "b", missing)
+
+	assert.Equal(t, []string{cache.LookupMiss, cache.LookupHit, cache.LookupMiss, cache.LookupNegativeHit}, results)
+}
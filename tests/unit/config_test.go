@@ -0,0 +1,387 @@
+package unit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config/configtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testConfigSecret = "0123456789abcdef0123456789abcdef"
+
+func validConfig() *config.Config {
+	return configtest.New(func(c *config.Config) {
+		c.Database.Password = "db-pass"
+		c.Redis.Password = "redis-pass"
+		c.JWT.Secret = testConfigSecret
+	})
+}
+
+func TestConfigtest_BuildsValidConfigInMemory(t *testing.T) {
+	// Variables that Read would pick up are ignored
+	t.Setenv("APP_SERVER_PORT", "1")
+
+	cfg := configtest.New(func(c *config.Config) { c.Server.Port = "8000" })
+	assert.NoError(t, config.Validate(cfg))
+	assert.Equal(t, "8000", cfg.Server.Port)
+	assert.Equal(t, configtest.Secret, cfg.JWT.Secret.Value())
+	assert.Equal(t, config.Default().Cache.TTL, cfg.Cache.TTL)
+}
+
+func TestConfig_MaskedHidesSecrets(t *testing.T) {
+	masked := config.Masked(validConfig())
+
+	assert.Equal(t, "******", masked["database"].(map[string]interface{})["password"])
+	assert.Equal(t, "******", masked["redis"].(map[string]interface{})["password"])
+	assert.Equal(t, "******", masked["jwt"].(map[string]interface{})["secret"])
+	assert.Equal(t, "postgres", masked["database"].(map[string]interface{})["driver"])
+	// Unset secrets stay empty so operators can see they are missing
+	assert.Equal(t, "", masked["mail"].(map[string]interface{})["password"])
+}
+
+func TestConfig_ValidateReportsEveryProblem(t *testing.T) {
+	assert.NoError(t, config.Validate(validConfig()))
+
+	cfg := validConfig()
+	cfg.Server.Port = "http"
+	cfg.Database.Driver = "sqlite"
+	cfg.JWT.Secret = ""
+
+	err := config.Validate(cfg)
+	assert.ErrorContains(t, err, "server.port")
+	assert.ErrorContains(t, err, "database.driver")
+	assert.ErrorContains(t, err, "jwt.secret")
+}
+
+func TestConfig_ValidateRejectsShortSecret(t *testing.T) {
+	cfg := validConfig()
+	cfg.JWT.Secret = "too-short"
+
+	assert.ErrorContains(t, config.Validate(cfg), "jwt.secret: must be at least 32 characters")
+}
+
+func TestConfig_ValidateChecksModeRequirements(t *testing.T) {
+	cfg := validConfig()
+	cfg.Redis.Mode = "sentinel"
+	cfg.Mail.Driver = "smtp"
+
+	err := config.Validate(cfg)
+	assert.ErrorContains(t, err, "redis.master_name")
+	assert.ErrorContains(t, err, "redis.addrs")
+	assert.ErrorContains(t, err, "mail.host")
+}
+
+func TestConfig_ValidateChecksCORSOrigins(t *testing.T) {
+	cfg := validConfig()
+	cfg.CORS.AllowOrigins = []string{"https://app.example.com", "https://*.example.com:8443", "http://localhost:3000"}
+	cfg.CORS.AllowCredentials = true
+	assert.NoError(t, config.Validate(cfg))
+
+	for _, origin := range []string{"app.example.com", "https://app.example.com/path", "https://*", "https://a.*.example.com"} {
+		cfg.CORS.AllowOrigins = []string{origin}
+		assert.ErrorContains(t, config.Validate(cfg), "cors.allow_origins", origin)
+	}
+
+	// Any origin is fine on its own but never with credentials
+	cfg.CORS.AllowOrigins = []string{"*"}
+	assert.ErrorContains(t, config.Validate(cfg), "cannot be combined with allow_credentials")
+	cfg.CORS.AllowCredentials = false
+	assert.NoError(t, config.Validate(cfg))
+}
+
+func TestConfig_ValidateChecksTrustedProxies(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.ProxyHeader = "X-Real-IP"
+	assert.ErrorContains(t, config.Validate(cfg), "server.trusted_proxies: required")
+
+	cfg.Server.TrustedProxies = []string{"10.0.0.1", "192.168.0.0/16", "lb.internal"}
+	assert.ErrorContains(t, config.Validate(cfg), `"lb.internal" is not an IP address`)
+
+	cfg.Server.TrustedProxies = cfg.Server.TrustedProxies[:2]
+	assert.NoError(t, config.Validate(cfg))
+}
+
+func TestConfig_AdminListensOnLoopbackByDefault(t *testing.T) {
+	chdirTemp(t)
+
+	cfg, err := config.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1", cfg.Admin.Host)
+}
+
+func TestSecret_NeverPrintsValue(t *testing.T) {
+	cfg := validConfig()
+
+	for _, out := range []string{
+		fmt.Sprintf("%v", cfg.JWT),
+		fmt.Sprintf("%+v", cfg.JWT),
+		fmt.Sprintf("%#v", cfg.JWT),
+		fmt.Sprint(cfg.JWT.Secret),
+	} {
+		assert.NotContains(t, out, testConfigSecret)
+		assert.Contains(t, out, "******")
+	}
+
+	data, err := json.Marshal(cfg.JWT)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), testConfigSecret)
+
+	data, err = yaml.Marshal(cfg.JWT)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), testConfigSecret)
+
+	assert.Equal(t, testConfigSecret, cfg.JWT.Secret.Value())
+}
+
+func TestConfig_ReadsSecretFromFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "jwt_secret")
+	require.NoError(t, os.WriteFile(path, []byte(testConfigSecret+"\n"), 0o600))
+
+	t.Setenv("JWT_SECRET_FILE", path)
+
+	cfg, err := readConfigIn(t)
+	require.NoError(t, err)
+	assert.Equal(t, testConfigSecret, cfg.JWT.Secret.Value())
+}
+
+func TestConfig_ReadFailsOnMissingSecretFile(t *testing.T) {
+	t.Setenv("JWT_SECRET_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	_, err := readConfigIn(t)
+	assert.ErrorContains(t, err, "JWT_SECRET_FILE")
+}
+
+// chdirTemp moves into an empty directory for the rest of the test, so no
+// config.yaml or .env interferes, and returns it.
+func chdirTemp(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+	return dir
+}
+
+// readConfigIn reads the configuration from an empty directory.
+func readConfigIn(t *testing.T) (*config.Config, error) {
+	t.Helper()
+	chdirTemp(t)
+	return config.Read()
+}
+
+func writeConfigFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+}
+
+// leafFields calls fn for every leaf setting of v with its dotted key.
+func leafFields(v reflect.Value, prefix string, fn func(key string, field reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		tag := v.Type().Field(i).Tag.Get("mapstructure")
+		if v.Field(i).Kind() == reflect.Struct {
+			leafFields(v.Field(i), prefix+tag+".", fn)
+			continue
+		}
+		fn(prefix+tag, v.Field(i))
+	}
+}
+
+func TestConfig_EveryKeyCanBeSetFromEnvironment(t *testing.T) {
+	defaults, err := readConfigIn(t)
+	require.NoError(t, err)
+
+	// Pick a value for each key that differs from its default
+	want := map[string]interface{}{}
+	leafFields(reflect.ValueOf(defaults).Elem(), "", func(key string, field reflect.Value) {
+		name := "APP_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		var value string
+		switch field.Kind() {
+		case reflect.String:
+			value = "env-" + key
+			want[key] = value
+		case reflect.Int:
+			value = strconv.Itoa(int(field.Int()) + 7)
+			want[key] = int(field.Int()) + 7
+		case reflect.Float64:
+			value = "0.37"
+			want[key] = 0.37
+		case reflect.Bool:
+			value = strconv.FormatBool(!field.Bool())
+			want[key] = !field.Bool()
+		case reflect.Slice:
+			value = "a,b"
+			want[key] = []string{"a", "b"}
+		case reflect.Map:
+			value = "grpc=debug"
+			want[key] = map[string]string{"grpc": "debug"}
+		default:
+			t.Fatalf("%s: no test value for %s", key, field.Kind())
+		}
+		t.Setenv(name, value)
+	})
+
+	cfg, err := readConfigIn(t)
+	require.NoError(t, err)
+	leafFields(reflect.ValueOf(cfg).Elem(), "", func(key string, field reflect.Value) {
+		got := field.Interface()
+		if s, ok := got.(config.Secret); ok {
+			got = s.Value()
+		}
+		assert.Equal(t, want[key], got, key)
+	})
+}
+
+func TestConfig_PrefixedVariableWinsOverBareName(t *testing.T) {
+	t.Setenv("DATABASE_HOST", "bare")
+	t.Setenv("APP_DATABASE_HOST", "prefixed")
+	t.Setenv("REDIS_HOST", "redis.internal")
+
+	cfg, err := readConfigIn(t)
+	require.NoError(t, err)
+	assert.Equal(t, "prefixed", cfg.Database.Host)
+	assert.Equal(t, "redis.internal", cfg.Redis.Host)
+}
+
+func TestConfig_EnvPrefixIsConfigurable(t *testing.T) {
+	t.Setenv("CONFIG_ENV_PREFIX", "starter")
+	t.Setenv("STARTER_SERVER_PORT", "9999")
+
+	cfg, err := readConfigIn(t)
+	require.NoError(t, err)
+	assert.Equal(t, "9999", cfg.Server.Port)
+}
+
+func TestConfig_ProfileLayersOverBaseFile(t *testing.T) {
+	dir := chdirTemp(t)
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: \"8000\"\nlog:\n  level: info\n  format: json\n")
+	writeConfigFile(t, dir, "config.staging.yaml", "log:\n  level: debug\n")
+	t.Setenv("APP_ENV", "staging")
+	t.Setenv("APP_LOG_FORMAT", "text")
+
+	cfg, err := config.Read()
+	require.NoError(t, err)
+	assert.Equal(t, "8000", cfg.Server.Port)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, "text", cfg.Log.Format)
+
+	cfg, err = config.Read(config.WithOverrides(map[string]string{"log.level": "warn"}))
+	require.NoError(t, err)
+	assert.Equal(t, "warn", cfg.Log.Level)
+}
+
+func TestConfig_MissingProfileIsAnError(t *testing.T) {
+	chdirTemp(t)
+
+	_, err := config.Read(config.WithProfile("production"))
+	assert.ErrorContains(t, err, "production")
+}
+
+func TestConfig_UnknownOverrideIsAnError(t *testing.T) {
+	chdirTemp(t)
+
+	_, err := config.Read(config.WithOverrides(map[string]string{"log.levl": "debug"}))
+	assert.ErrorContains(t, err, "log.levl")
+}
+
+func TestWatcher_AppliesSafeChangesAndRejectsOthers(t *testing.T) {
+	dir := chdirTemp(t)
+	t.Setenv("APP_JWT_SECRET", testConfigSecret)
+	t.Setenv("APP_DATABASE_DATABASE", "app")
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: \"8000\"\nlog:\n  level: info\n")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	watcher := config.NewWatcher(cfg)
+
+	var changes []string
+	config.Subscribe(watcher, func(c *config.Config) config.LogConfig { return c.Log }, func(old, new config.LogConfig) {
+		changes = append(changes, old.Level+"->"+new.Level)
+	})
+	serverChanged := false
+	config.Subscribe(watcher, func(c *config.Config) config.ServerConfig { return c.Server }, func(_, _ config.ServerConfig) {
+		serverChanged = true
+	})
+
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: \"9000\"\nlog:\n  level: debug\n")
+	err = watcher.Reload()
+	assert.ErrorContains(t, err, "server.port")
+	assert.Equal(t, []string{"info->debug"}, changes)
+	assert.False(t, serverChanged)
+	assert.Equal(t, "debug", watcher.Current().Log.Level)
+	assert.Equal(t, "8000", watcher.Current().Server.Port)
+	// The loaded configuration itself is never modified
+	assert.Equal(t, "info", cfg.Log.Level)
+
+	// Reloading the same files changes nothing
+	writeConfigFile(t, dir, "config.yaml", "server:\n  port: \"8000\"\nlog:\n  level: debug\n")
+	assert.NoError(t, watcher.Reload())
+	assert.Len(t, changes, 1)
+}
+
+func TestWatcher_RejectsInvalidConfiguration(t *testing.T) {
+	dir := chdirTemp(t)
+	t.Setenv("APP_JWT_SECRET", testConfigSecret)
+	t.Setenv("APP_DATABASE_DATABASE", "app")
+	writeConfigFile(t, dir, "config.yaml", "log:\n  level: info\n")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	watcher := config.NewWatcher(cfg)
+
+	writeConfigFile(t, dir, "config.yaml", "log:\n  level: loud\n")
+	assert.ErrorContains(t, watcher.Reload(), "log.level")
+	assert.Equal(t, "info", watcher.Current().Log.Level)
+}
+
+func TestWatcher_RejectsSafeChangesInvalidWithRunningValues(t *testing.T) {
+	dir := chdirTemp(t)
+	t.Setenv("APP_JWT_SECRET", testConfigSecret)
+	t.Setenv("APP_DATABASE_DATABASE", "app")
+	writeConfigFile(t, dir, "config.yaml", "cors:\n  allow_origins: [\"https://app.example.com\"]\n  allow_credentials: true\n")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	watcher := config.NewWatcher(cfg)
+	notified := false
+	config.Subscribe(watcher, func(c *config.Config) []string { return c.CORS.AllowOrigins }, func(_, _ []string) {
+		notified = true
+	})
+
+	// Valid on its own, but allow_credentials needs a restart and stays on
+	writeConfigFile(t, dir, "config.yaml", "cors:\n  allow_origins: [\"*\"]\n  allow_credentials: false\n")
+	assert.ErrorContains(t, watcher.Reload(), "allow_credentials")
+	assert.False(t, notified)
+	assert.Equal(t, []string{"https://app.example.com"}, watcher.Current().CORS.AllowOrigins)
+	assert.True(t, watcher.Current().CORS.AllowCredentials)
+}
+
+func TestWatcher_ReloadsWhenFileChanges(t *testing.T) {
+	dir := chdirTemp(t)
+	t.Setenv("APP_JWT_SECRET", testConfigSecret)
+	t.Setenv("APP_DATABASE_DATABASE", "app")
+	writeConfigFile(t, dir, "config.yaml", "log:\n  level: info\n")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	watcher := config.NewWatcher(cfg)
+	require.NoError(t, watcher.Start(func(error) {}))
+
+	writeConfigFile(t, dir, "config.yaml", "log:\n  level: warn\n")
+	assert.Eventually(t, func() bool {
+		return watcher.Current().Log.Level == "warn"
+	}, 2*time.Second, 20*time.Millisecond)
+}
@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/mailer"
+	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type recordingMailer struct {
+	sent []mailer.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var testVerificationConfig = config.VerificationConfig{
+	RequireVerifiedEmail: true,
+	TokenExpire:          1,
+	URL:                  "http://localhost/verify-email",
+}
+
+func TestEmailVerification_SendAndVerify(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mail := &recordingMailer{}
+	mockAudit := new(MockAuditService)
+	mockAudit.On("Record", mock.Anything, mock.Anything).Return(nil)
+	verificationService := serviceimpl.NewEmailVerificationService(mockRepo, cache.NewNoop(), testCacheConfig, mail, passthroughTransactor{}, mockAudit, testJWTConfig, testVerificationConfig)
+
+	ctx := context.Background()
+	user := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+
+	assert.NoError(t, verificationService.SendVerification(ctx, user))
+	assert.Len(t, mail.sent, 1)
+	assert.Equal(t, user.Email, mail.sent[0].To)
+
+	token := extractToken(t, mail.sent[0].Body)
+
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRepo.On("Update", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+
+	err := verificationService.VerifyEmail(ctx, &dto.VerifyEmailRequest{Token: token})
+
+	assert.NoError(t, err)
+	assert.True(t, user.EmailVerified())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEmailVerification_RejectsTokenForOldAddress(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mail := &recordingMailer{}
+	mockAudit := new(MockAuditService)
+	mockAudit.On("Record", mock.Anything, mock.Anything).Return(nil)
+	verificationService := serviceimpl.NewEmailVerificationService(mockRepo, cache.NewNoop(), testCacheConfig, mail, passthroughTransactor{}, mockAudit, testJWTConfig, testVerificationConfig)
+
+	ctx := context.Background()
+	user := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+	assert.NoError(t, verificationService.SendVerification(ctx, user))
+	token := extractToken(t, mail.sent[0].Body)
+
+	changed := &entity.User{ID: 1, Name: "John Doe", Email: "john.doe@example.com"}
+	mockRepo.On("GetByID", ctx, user.ID).Return(changed, nil)
+
+	err := verificationService.VerifyEmail(ctx, &dto.VerifyEmailRequest{Token: token})
+
+	assert.Error(t, err)
+	assert.False(t, changed.EmailVerified())
+}
+
+func TestAuthService_Login_BlocksUnverifiedEmail(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	authService := serviceimpl.NewAuthService(mockRepo, new(MockRecoveryCodeRepository), testJWTConfig, testMFAConfig, testVerificationConfig)
+
+	ctx := context.Background()
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &entity.User{ID: 1, Email: "john@example.com", Password: string(hashed), Role: entity.RoleUser, IsActive: true}
+	mockRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+
+	result, err := authService.Login(ctx, &dto.LoginRequest{Email: user.Email, Password: "password123"})
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not been verified")
+}
+
+func extractToken(t *testing.T, body string) string {
+	t.Helper()
+	for _, field := range strings.Fields(body) {
+		if strings.HasPrefix(field, testVerificationConfig.URL) {
+			link, err := url.Parse(field)
+			assert.NoError(t, err)
+			return link.Query().Get("token")
+		}
+	}
+	t.Fatal("verification link not found in mail body")
+	return ""
+}
@@ -0,0 +1,71 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/health"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthChecker_ReportsEachCheck(t *testing.T) {
+	checker := health.NewChecker(config.HealthConfig{Timeout: 1000})
+	checker.Register("database", func(ctx context.Context) error { return nil })
+	checker.RegisterOptional("redis", func(ctx context.Context) error { return errors.New("connection refused") })
+
+	report := checker.Report(context.Background())
+	assert.Equal(t, health.StatusDegraded, report.Status)
+	assert.True(t, report.Ready())
+	assert.Equal(t, health.StatusUp, report.Checks["database"].Status)
+	assert.Equal(t, health.StatusDown, report.Checks["redis"].Status)
+	assert.Equal(t, "connection refused", report.Checks["redis"].Error)
+
+	checker.Register("grpc", func(ctx context.Context) error { return errors.New("not listening") })
+	report = checker.Report(context.Background())
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.False(t, report.Ready())
+}
+
+func TestHealthChecker_TimesOutSlowChecks(t *testing.T) {
+	checker := health.NewChecker(config.HealthConfig{Timeout: 20})
+	checker.Register("database", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	report := checker.Report(context.Background())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, health.StatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["database"].Error)
+}
+
+func TestHealthChecker_CachesResults(t *testing.T) {
+	var calls atomic.Int32
+	checker := health.NewChecker(config.HealthConfig{Timeout: 1000, CacheTTL: 60000})
+	checker.Register("database", func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	})
+
+	for i := 0; i < 5; i++ {
+		checker.Report(context.Background())
+	}
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestHealthChecker_NotReadyAfterShutdown(t *testing.T) {
+	checker := health.NewChecker(config.HealthConfig{Timeout: 1000})
+	checker.Register("database", func(ctx context.Context) error { return nil })
+	assert.True(t, checker.Report(context.Background()).Ready())
+
+	checker.Shutdown()
+	report := checker.Report(context.Background())
+	assert.Equal(t, health.StatusShuttingDown, report.Status)
+	assert.False(t, report.Ready())
+}
@@ -0,0 +1,108 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/lifecycle"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func recordingHook(name string, events *[]string, startErr error) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			*events = append(*events, "start "+name)
+			return startErr
+		},
+		OnStop: func(ctx context.Context) error {
+			*events = append(*events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestLifecycle_StopsInReverseOrder(t *testing.T) {
+	logger.Init("silent")
+	var events []string
+	lc := lifecycle.New(time.Second)
+	lc.Append(lifecycle.Hook{Name: "database", OnStop: func(ctx context.Context) error {
+		events = append(events, "stop database")
+		return nil
+	}})
+	lc.Append(recordingHook("grpc", &events, nil))
+	lc.Append(recordingHook("http", &events, nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, lc.Run(ctx))
+
+	assert.Equal(t, []string{"start grpc", "start http", "stop http", "stop grpc", "stop database"}, events)
+}
+
+func TestLifecycle_StartupErrorStopsStartedHooks(t *testing.T) {
+	logger.Init("silent")
+	var events []string
+	lc := lifecycle.New(time.Second)
+	lc.Append(recordingHook("grpc", &events, nil))
+	lc.Append(recordingHook("http", &events, errors.New("address already in use")))
+	lc.Append(recordingHook("admin", &events, nil))
+
+	err := lc.Run(context.Background())
+	assert.ErrorContains(t, err, "start http: address already in use")
+	assert.Equal(t, []string{"start grpc", "start http", "stop grpc"}, events)
+}
+
+func TestLifecycle_BackgroundFailureStopsProcess(t *testing.T) {
+	logger.Init("silent")
+	var events []string
+	lc := lifecycle.New(time.Second)
+	lc.Append(recordingHook("database", &events, nil))
+	lc.Append(lifecycle.Hook{
+		Name: "grpc",
+		OnStart: func(ctx context.Context) error {
+			lc.Go("grpc", func() error { return errors.New("listener closed") })
+			return nil
+		},
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- lc.Run(context.Background()) }()
+
+	select {
+	case err := <-done:
+		assert.ErrorContains(t, err, "grpc: listener closed")
+		assert.Equal(t, []string{"start database", "stop database"}, events)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after a background failure")
+	}
+}
+
+func TestLifecycle_SharesStopDeadline(t *testing.T) {
+	logger.Init("silent")
+	lc := lifecycle.New(50 * time.Millisecond)
+	var closedErr error
+	lc.Append(lifecycle.Hook{Name: "database", OnStop: func(ctx context.Context) error {
+		closedErr = ctx.Err()
+		return nil
+	}})
+	lc.Append(lifecycle.Hook{Name: "http", OnStop: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := lc.Run(ctx)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "stop http")
+	// Later hooks still run once the deadline has passed
+	assert.ErrorIs(t, closedErr, context.DeadlineExceeded)
+}
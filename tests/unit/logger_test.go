@@ -0,0 +1,165 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/requestid"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_FromContextAddsRequestFields(t *testing.T) {
+	logger.Init("info")
+	defer logger.Init("silent")
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+
+	ctx := requestid.WithID(context.Background(), "req-123")
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{UserID: 7})
+	logger.FromContext(ctx).WithField("user_id", 9).Info("Updated user")
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-123", entry["request_id"])
+	assert.Equal(t, float64(7), entry["actor_id"])
+	assert.Equal(t, float64(9), entry["user_id"])
+}
+
+func setupLogger(t *testing.T, cfg config.LogConfig) *bytes.Buffer {
+	t.Helper()
+	assert.NoError(t, logger.Setup(cfg))
+	t.Cleanup(func() { logger.Init("silent") })
+
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	return &buf
+}
+
+func logLines(buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		if json.Unmarshal([]byte(line), &entry) == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func TestLogger_TypedFieldsAndRedaction(t *testing.T) {
+	buf := setupLogger(t, config.LogConfig{Level: "info", RedactKeys: []string{"password", "token"}})
+
+	logger.Named("service").Info(context.Background(), "Signed in",
+		logger.Uint("user_id", 3),
+		logger.String("access_token", "abc"),
+		logger.String("Password", "hunter2"),
+		logger.Err(errors.New("boom")),
+	)
+
+	entries := logLines(buf)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "Signed in", entries[0]["msg"])
+	assert.Equal(t, "service", entries[0]["logger"])
+	assert.Equal(t, float64(3), entries[0]["user_id"])
+	assert.Equal(t, "[REDACTED]", entries[0]["access_token"])
+	assert.Equal(t, "[REDACTED]", entries[0]["Password"])
+	assert.Equal(t, "boom", entries[0]["error"])
+}
+
+func TestLogger_PackageLevels(t *testing.T) {
+	buf := setupLogger(t, config.LogConfig{Level: "warn", Levels: map[string]string{"cache": "debug"}})
+
+	logger.Named("cache").Debug(context.Background(), "Cache miss")
+	logger.Named("service").Info(context.Background(), "Creating user")
+	logger.Named("service").Warn(context.Background(), "Slow query")
+	logger.FromContext(context.Background()).Info("Unnamed info")
+
+	var msgs []interface{}
+	for _, entry := range logLines(buf) {
+		msgs = append(msgs, entry["msg"])
+	}
+	assert.Equal(t, []interface{}{"Cache miss", "Slow query"}, msgs)
+}
+
+func TestLogger_RejectsInvalidConfig(t *testing.T) {
+	t.Cleanup(func() { logger.Init("silent") })
+
+	assert.Error(t, logger.Setup(config.LogConfig{Level: "info", Format: "xml"}))
+	assert.Error(t, logger.Setup(config.LogConfig{Level: "info", Levels: map[string]string{"cache": "loud"}}))
+}
+
+func TestLogger_TextFormat(t *testing.T) {
+	buf := setupLogger(t, config.LogConfig{Level: "info", Format: "text", RedactKeys: []string{"secret"}})
+
+	logger.Named("http").Info(context.Background(), "HTTP Request", logger.Int("status", 200), logger.String("client_secret", "s3"))
+
+	out := buf.String()
+	assert.Contains(t, out, `msg="HTTP Request"`)
+	assert.Contains(t, out, "status=200")
+	assert.Contains(t, out, "client_secret=\"[REDACTED]\"")
+	assert.NotContains(t, out, "s3")
+}
+
+func TestLogger_Sampling(t *testing.T) {
+	buf := setupLogger(t, config.LogConfig{
+		Level:    "info",
+		Sampling: config.LogSamplingConfig{Enabled: true, Initial: 2, Thereafter: 3, Interval: 60},
+	})
+
+	log := logger.Named("http")
+	for i := 0; i < 10; i++ {
+		log.Info(context.Background(), "HTTP Request")
+		log.Error(context.Background(), "Failed")
+	}
+
+	counts := map[interface{}]int{}
+	for _, entry := range logLines(buf) {
+		counts[entry["msg"]]++
+	}
+	// The first 2, then the 5th and 8th
+	assert.Equal(t, 4, counts["HTTP Request"])
+	assert.Equal(t, 10, counts["Failed"])
+}
+
+func TestLogger_LevelHandler(t *testing.T) {
+	buf := setupLogger(t, config.LogConfig{Level: "info"})
+	handler := logger.LevelHandler()
+
+	put := func(body string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(body)))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, put(`{"package":"cache","level":"debug"}`))
+	assert.Equal(t, http.StatusBadRequest, put(`{"level":"loud"}`))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/log/level", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"info","packages":{"cache":"debug"}}`, rec.Body.String())
+
+	buf.Reset()
+	logger.Named("cache").Debug(context.Background(), "Cache miss")
+	logger.Named("service").Debug(context.Background(), "Hidden")
+	assert.Contains(t, buf.String(), "Cache miss")
+	assert.NotContains(t, buf.String(), "Hidden")
+
+	assert.Equal(t, http.StatusOK, put(`{"package":"cache","level":""}`))
+	buf.Reset()
+	logger.Named("cache").Debug(context.Background(), "Cache miss")
+	assert.Empty(t, buf.String())
+}
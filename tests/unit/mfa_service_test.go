@@ -0,0 +1,176 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
+	iUc "github.com/faizalnurrozi/go-starter-kit/internal/service/interfaces"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newMFAService() (iUc.MFAService, *MockUserRepository, *MockRecoveryCodeRepository, *MockAuditService) {
+	logger.Init("silent")
+	userRepo := new(MockUserRepository)
+	recoveryRepo := new(MockRecoveryCodeRepository)
+	auditor := new(MockAuditService)
+	return serviceimpl.NewMFAService(userRepo, recoveryRepo, passthroughTransactor{}, auditor, testMFAConfig), userRepo, recoveryRepo, auditor
+}
+
+// enrolledUser returns a user with a confirmed TOTP factor along with a
+// currently valid code and one that is not.
+func enrolledUser(t *testing.T) (*entity.User, string, string) {
+	t.Helper()
+	secret, err := auth.GenerateTOTPSecret()
+	assert.NoError(t, err)
+	code, err := auth.TOTPCode(secret, time.Now())
+	assert.NoError(t, err)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	enabledAt := time.Now()
+	return &entity.User{ID: 4, Email: "jane@example.com", Role: entity.RoleUser, IsActive: true, TOTPSecret: secret, TOTPEnabledAt: &enabledAt}, code, wrong
+}
+
+func TestMFAService_ConfirmTOTP(t *testing.T) {
+	service, userRepo, recoveryRepo, auditor := newMFAService()
+	ctx := context.Background()
+	user, code, _ := enrolledUser(t)
+	user.TOTPEnabledAt = nil
+
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	userRepo.On("UpdateTOTPLastStep", ctx, user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+	recoveryRepo.On("Replace", ctx, user.ID, mock.MatchedBy(func(codes []entity.RecoveryCode) bool {
+		return len(codes) == testMFAConfig.RecoveryCodeCount
+	})).Return(nil)
+	auditor.On("Record", ctx, mock.MatchedBy(func(e audit.Entry) bool {
+		return e.Action == audit.ActionUserMFAEnabled && e.TargetID == user.ID
+	})).Return(nil)
+
+	result, err := service.ConfirmTOTP(ctx, user.ID, &dto.TOTPCodeRequest{Code: code})
+
+	assert.NoError(t, err)
+	assert.Len(t, result.RecoveryCodes, testMFAConfig.RecoveryCodeCount)
+	assert.True(t, user.MFAEnabled())
+	userRepo.AssertExpectations(t)
+	recoveryRepo.AssertExpectations(t)
+	auditor.AssertExpectations(t)
+}
+
+func TestMFAService_ConfirmTOTP_InvalidCode(t *testing.T) {
+	service, userRepo, recoveryRepo, _ := newMFAService()
+	ctx := context.Background()
+	user, _, wrong := enrolledUser(t)
+	user.TOTPEnabledAt = nil
+
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+
+	_, err := service.ConfirmTOTP(ctx, user.ID, &dto.TOTPCodeRequest{Code: wrong})
+
+	assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).Code)
+	assert.False(t, user.MFAEnabled())
+	userRepo.AssertNotCalled(t, "Update", ctx, user)
+	recoveryRepo.AssertNotCalled(t, "Replace", ctx, user.ID, mock.Anything)
+}
+
+func TestMFAService_DisableTOTP_RequiresValidCode(t *testing.T) {
+	service, userRepo, recoveryRepo, auditor := newMFAService()
+	ctx := context.Background()
+	user, code, wrong := enrolledUser(t)
+
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+
+	err := service.DisableTOTP(ctx, user.ID, &dto.TOTPCodeRequest{Code: wrong})
+	assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).Code)
+	assert.True(t, user.MFAEnabled())
+	userRepo.AssertNotCalled(t, "Update", ctx, user)
+	recoveryRepo.AssertNotCalled(t, "DeleteByUserID", ctx, user.ID)
+
+	userRepo.On("UpdateTOTPLastStep", ctx, user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+	recoveryRepo.On("DeleteByUserID", ctx, user.ID).Return(nil)
+	auditor.On("Record", ctx, mock.MatchedBy(func(e audit.Entry) bool {
+		return e.Action == audit.ActionUserMFADisabled
+	})).Return(nil)
+
+	err = service.DisableTOTP(ctx, user.ID, &dto.TOTPCodeRequest{Code: code})
+	assert.NoError(t, err)
+	assert.False(t, user.MFAEnabled())
+	userRepo.AssertExpectations(t)
+	recoveryRepo.AssertExpectations(t)
+}
+
+func TestMFAService_DisableTOTP_ForbiddenForRequiredRole(t *testing.T) {
+	service, userRepo, _, _ := newMFAService()
+	ctx := context.Background()
+	user, code, _ := enrolledUser(t)
+	user.Role = entity.RoleAdmin
+
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+
+	err := service.DisableTOTP(ctx, user.ID, &dto.TOTPCodeRequest{Code: code})
+
+	assert.Equal(t, http.StatusForbidden, err.(*errors.AppError).Code)
+	assert.True(t, user.MFAEnabled())
+}
+
+func TestMFAService_RegenerateRecoveryCodes_RequiresValidCode(t *testing.T) {
+	service, userRepo, recoveryRepo, auditor := newMFAService()
+	ctx := context.Background()
+	user, code, wrong := enrolledUser(t)
+
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+
+	_, err := service.RegenerateRecoveryCodes(ctx, user.ID, &dto.TOTPCodeRequest{Code: wrong})
+	assert.Equal(t, http.StatusBadRequest, err.(*errors.AppError).Code)
+	recoveryRepo.AssertNotCalled(t, "Replace", ctx, user.ID, mock.Anything)
+
+	userRepo.On("UpdateTOTPLastStep", ctx, user.ID, mock.AnythingOfType("int64")).Return(true, nil)
+	recoveryRepo.On("Replace", ctx, user.ID, mock.AnythingOfType("[]entity.RecoveryCode")).Return(nil)
+	auditor.On("Record", ctx, mock.MatchedBy(func(e audit.Entry) bool {
+		return e.Action == audit.ActionUserRecoveryCodes
+	})).Return(nil)
+
+	result, err := service.RegenerateRecoveryCodes(ctx, user.ID, &dto.TOTPCodeRequest{Code: code})
+	assert.NoError(t, err)
+	assert.Len(t, result.RecoveryCodes, testMFAConfig.RecoveryCodeCount)
+	recoveryRepo.AssertExpectations(t)
+	auditor.AssertExpectations(t)
+}
+
+func TestMFAService_Reset(t *testing.T) {
+	service, userRepo, recoveryRepo, auditor := newMFAService()
+	ctx := context.Background()
+	user, _, _ := enrolledUser(t)
+	lockedUntil := time.Now().Add(time.Hour)
+	user.MFAFailedAttempts = 3
+	user.MFALockedUntil = &lockedUntil
+
+	userRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	userRepo.On("Update", ctx, user).Return(nil)
+	recoveryRepo.On("DeleteByUserID", ctx, user.ID).Return(nil)
+	auditor.On("Record", ctx, mock.MatchedBy(func(e audit.Entry) bool {
+		return e.Action == audit.ActionUserMFAReset && e.TargetID == user.ID
+	})).Return(nil)
+
+	err := service.Reset(ctx, user.ID)
+
+	assert.NoError(t, err)
+	assert.False(t, user.MFAEnabled())
+	assert.False(t, user.MFALocked(time.Now()))
+	userRepo.AssertExpectations(t)
+	recoveryRepo.AssertExpectations(t)
+	auditor.AssertExpectations(t)
+}
@@ -0,0 +1,188 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/config"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	"github.com/faizalnurrozi/go-starter-kit/internal/ratelimit"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// windowStart is the start of a one-minute window.
+var windowStart = time.Unix(1_700_000_040, 0)
+
+func testStores(t *testing.T) map[string]ratelimit.Store {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return map[string]ratelimit.Store{
+		"memory": ratelimit.NewMemoryStore(),
+		"redis":  ratelimit.NewRedisStore(client, "test"),
+	}
+}
+
+func TestStore_SlidingWindow(t *testing.T) {
+	rule := ratelimit.Rule{Requests: 3, Window: time.Minute}
+	ctx := context.Background()
+
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 3; i++ {
+				result, err := store.Take(ctx, "k", rule, windowStart.Add(time.Duration(i)*time.Second))
+				require.NoError(t, err)
+				assert.True(t, result.Allowed)
+				assert.Equal(t, 2-i, result.Remaining)
+			}
+
+			result, err := store.Take(ctx, "k", rule, windowStart.Add(10*time.Second))
+			require.NoError(t, err)
+			assert.False(t, result.Allowed)
+			assert.Equal(t, 0, result.Remaining)
+			assert.Equal(t, 50*time.Second, result.Reset)
+			// The next window starts with 3 weighted requests; one third of
+			// the way in, they count as 2 and leave room for one more
+			assert.Equal(t, 70*time.Second, result.RetryAfter)
+			now := windowStart.Add(10*time.Second + result.RetryAfter)
+
+			// Other keys have their own counters
+			result, err = store.Take(ctx, "other", rule, windowStart.Add(10*time.Second))
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+
+			result, err = store.Take(ctx, "k", rule, now.Add(-time.Second))
+			require.NoError(t, err)
+			assert.False(t, result.Allowed, "still limited just before RetryAfter")
+			assert.Equal(t, time.Second, result.RetryAfter)
+			result, err = store.Take(ctx, "k", rule, now)
+			require.NoError(t, err)
+			assert.True(t, result.Allowed, "allowed after RetryAfter")
+
+			// Two idle windows later the client starts afresh
+			result, err = store.Take(ctx, "k", rule, windowStart.Add(3*time.Minute))
+			require.NoError(t, err)
+			assert.Equal(t, 2, result.Remaining)
+		})
+	}
+}
+
+func TestResult_Headers(t *testing.T) {
+	result := ratelimit.Result{Limit: 5, Remaining: 0, Window: time.Minute, Reset: 1500 * time.Millisecond, RetryAfter: 200 * time.Millisecond}
+
+	assert.Equal(t, map[string]string{
+		"RateLimit-Limit":     "5",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "2",
+		"RateLimit-Policy":    "5;w=60",
+		"Retry-After":         "1",
+	}, result.Headers())
+
+	result.Allowed = true
+	assert.NotContains(t, result.Headers(), "Retry-After")
+}
+
+// recordingStore records the keys it counts and allows everything unless
+// err is set.
+type recordingStore struct {
+	keys  []string
+	rules []ratelimit.Rule
+	err   error
+}
+
+func (s *recordingStore) Take(ctx context.Context, key string, rule ratelimit.Rule, now time.Time) (ratelimit.Result, error) {
+	s.keys = append(s.keys, key)
+	s.rules = append(s.rules, rule)
+	return ratelimit.Result{Allowed: true, Limit: rule.Requests}, s.err
+}
+
+func testRateLimitConfig() config.RateLimitConfig {
+	return config.RateLimitConfig{
+		Enabled:      true,
+		Driver:       "memory",
+		FailOpen:     true,
+		KeyBy:        []string{"api_key", "user", "ip"},
+		APIKeyHeader: "X-API-Key",
+		Default:      "100/1m",
+		Routes: []string{
+			"POST /api/v1/auth/login 5/1m",
+			"/api/v1/audit-events* 10/1s",
+			"/api/v1/users/export off",
+		},
+		Methods: []string{"/user.UserService/CreateUser 2/1m"},
+	}
+}
+
+func TestLimiter_MatchesRulesAndKeysClients(t *testing.T) {
+	store := &recordingStore{}
+	limiter, err := ratelimit.NewLimiter(store, testRateLimitConfig())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, limited := limiter.TakeHTTP(ctx, "POST", "/api/v1/auth/login", ratelimit.Client{IP: "10.0.0.1"})
+	assert.True(t, limited)
+	_, limited = limiter.TakeHTTP(ctx, "GET", "/api/v1/audit-events/7", ratelimit.Client{UserID: 7, IP: "10.0.0.1"})
+	assert.True(t, limited)
+	_, limited = limiter.TakeHTTP(ctx, "GET", "/api/v1/users", ratelimit.Client{APIKey: "k", UserID: 7, IP: "10.0.0.1"})
+	assert.True(t, limited)
+	_, limited = limiter.TakeGRPC(ctx, "/user.UserService/CreateUser", ratelimit.Client{IP: "10.0.0.2"})
+	assert.True(t, limited)
+	// Unverified API keys without a user also count against the IP
+	_, limited = limiter.TakeHTTP(ctx, "GET", "/api/v1/users", ratelimit.Client{APIKey: "k", IP: "10.0.0.3"})
+	assert.True(t, limited)
+
+	// Exempt routes are not counted
+	_, limited = limiter.TakeHTTP(ctx, "GET", "/api/v1/users/export", ratelimit.Client{IP: "10.0.0.1"})
+	assert.False(t, limited)
+
+	require.Len(t, store.keys, 6)
+	assert.Equal(t, "POST /api/v1/auth/login:ip:10.0.0.1", store.keys[0])
+	assert.Equal(t, ratelimit.Rule{Requests: 5, Window: time.Minute}, store.rules[0])
+	assert.Equal(t, "/api/v1/audit-events*:user:7", store.keys[1])
+	assert.Equal(t, ratelimit.Rule{Requests: 10, Window: time.Second}, store.rules[1])
+	assert.Regexp(t, `^default:key:[0-9a-f]{32}$`, store.keys[2])
+	assert.Equal(t, "/user.UserService/CreateUser:ip:10.0.0.2", store.keys[3])
+	assert.Regexp(t, `^default:key:[0-9a-f]{32}$`, store.keys[4])
+	assert.Equal(t, "default:ip:10.0.0.3", store.keys[5])
+}
+
+func TestLimiter_UpdateReplacesRules(t *testing.T) {
+	store := &recordingStore{}
+	cfg := testRateLimitConfig()
+	limiter, err := ratelimit.NewLimiter(store, cfg)
+	require.NoError(t, err)
+
+	cfg.Enabled = false
+	require.NoError(t, limiter.Update(cfg))
+	_, limited := limiter.TakeHTTP(context.Background(), "GET", "/api/v1/users", ratelimit.Client{IP: "10.0.0.1"})
+	assert.False(t, limited)
+	assert.Empty(t, store.keys)
+
+	cfg.Default = "often"
+	assert.Error(t, limiter.Update(cfg))
+}
+
+func TestLimiter_StoreErrors(t *testing.T) {
+	logger.Init("silent")
+	store := &recordingStore{err: errors.New("redis down")}
+	cfg := testRateLimitConfig()
+
+	limiter, err := ratelimit.NewLimiter(store, cfg)
+	require.NoError(t, err)
+	_, limited := limiter.TakeHTTP(context.Background(), "GET", "/api/v1/users", ratelimit.Client{IP: "10.0.0.1"})
+	assert.False(t, limited, "fails open")
+
+	cfg.FailOpen = false
+	require.NoError(t, limiter.Update(cfg))
+	result, limited := limiter.TakeHTTP(context.Background(), "GET", "/api/v1/users", ratelimit.Client{IP: "10.0.0.1"})
+	assert.True(t, limited)
+	assert.False(t, result.Allowed, "fails closed")
+	assert.Equal(t, time.Second, result.RetryAfter)
+}
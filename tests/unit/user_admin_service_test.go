@@ -0,0 +1,100 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
+	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
+	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
+	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func TestUserAdminService_CreateAdmin(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	service := serviceimpl.NewUserAdminService(mockRepo, cache.NewNoop(), testCacheConfig, passthroughTransactor{}, mockAudit)
+
+	ctx := context.Background()
+	req := &dto.CreateUserRequest{Name: "Ops", Email: "ops@example.com", Password: "password123"}
+
+	mockRepo.On("GetByEmail", ctx, req.Email).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(user *entity.User) bool {
+		return user.Role == entity.RoleAdmin && user.EmailVerifiedAt != nil &&
+			bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) == nil
+	})).Return(nil)
+	mockAudit.On("Record", ctx, mock.MatchedBy(func(entry audit.Entry) bool {
+		return entry.Action == audit.ActionUserCreate && entry.Actor != nil && entry.Actor.Type == audit.ActorSystem
+	})).Return(nil)
+
+	result, err := service.CreateAdmin(ctx, req)
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RoleAdmin, result.Role)
+	mockRepo.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestUserAdminService_CreateAdmin_EmailExists(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	service := serviceimpl.NewUserAdminService(mockRepo, cache.NewNoop(), testCacheConfig, passthroughTransactor{}, new(MockAuditService))
+
+	ctx := context.Background()
+	mockRepo.On("GetByEmail", ctx, "ops@example.com").Return(&entity.User{ID: 1}, nil)
+
+	_, err := service.CreateAdmin(ctx, &dto.CreateUserRequest{Name: "Ops", Email: "ops@example.com", Password: "password123"})
+
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, appErr.Code)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUserAdminService_SetPassword(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	service := serviceimpl.NewUserAdminService(mockRepo, cache.NewNoop(), testCacheConfig, passthroughTransactor{}, mockAudit)
+
+	ctx := context.Background()
+	user := &entity.User{ID: 4, Email: "jane@example.com", Password: "old-hash"}
+	mockRepo.On("GetByEmail", ctx, user.Email).Return(user, nil)
+	mockRepo.On("Update", ctx, mock.MatchedBy(func(u *entity.User) bool {
+		return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte("new-password")) == nil
+	})).Return(nil)
+	mockAudit.On("Record", ctx, mock.MatchedBy(func(entry audit.Entry) bool {
+		return entry.Action == audit.ActionUserPasswordSet && entry.TargetID == user.ID
+	})).Return(nil)
+
+	err := service.SetPassword(ctx, &dto.SetPasswordRequest{Email: user.Email, Password: "new-password"})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestUserAdminService_SetPassword_UnknownUser(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	service := serviceimpl.NewUserAdminService(mockRepo, cache.NewNoop(), testCacheConfig, passthroughTransactor{}, new(MockAuditService))
+
+	ctx := context.Background()
+	mockRepo.On("GetByEmail", ctx, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.SetPassword(ctx, &dto.SetPasswordRequest{Email: "nobody@example.com", Password: "new-password"})
+
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, appErr.Code)
+}
@@ -2,10 +2,17 @@ package unit
 
 import (
 	"context"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/faizalnurrozi/go-starter-kit/internal/audit"
+	"github.com/faizalnurrozi/go-starter-kit/internal/auth"
+	"github.com/faizalnurrozi/go-starter-kit/internal/cache"
 	dto "github.com/faizalnurrozi/go-starter-kit/internal/dto/request"
+	response "github.com/faizalnurrozi/go-starter-kit/internal/dto/response"
 	"github.com/faizalnurrozi/go-starter-kit/internal/entity"
+	"github.com/faizalnurrozi/go-starter-kit/internal/errors"
 	"github.com/faizalnurrozi/go-starter-kit/internal/logger"
 	serviceimpl "github.com/faizalnurrozi/go-starter-kit/internal/service/impl"
 
@@ -56,10 +63,52 @@ func (m *MockUserRepository) Count(ctx context.Context) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockUserRepository) UpdateTOTPLastStep(ctx context.Context, id uint, step int64) (bool, error) {
+	args := m.Called(ctx, id, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) RecordMFAFailure(ctx context.Context, id uint) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockUserRepository) LockMFA(ctx context.Context, id uint, until time.Time) error {
+	args := m.Called(ctx, id, until)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) ResetMFAFailures(ctx context.Context, id uint) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// passthroughTransactor runs the function without a real transaction.
+type passthroughTransactor struct{}
+
+func (passthroughTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) Record(ctx context.Context, entry audit.Entry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditService) List(ctx context.Context, req *dto.ListAuditEventsRequest) (*response.AuditEventListResponse, error) {
+	args := m.Called(ctx, req)
+	return args.Get(0).(*response.AuditEventListResponse), args.Error(1)
+}
+
 func TestUserService_Create_Success(t *testing.T) {
 	logger.Init("info")
 	mockRepo := new(MockUserRepository)
-	userService := serviceimpl.NewUserService(mockRepo, nil)
+	mockAudit := new(MockAuditService)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewNoop(), testCacheConfig, nil, passthroughTransactor{}, mockAudit)
 
 	ctx := context.Background()
 	req := &dto.CreateUserRequest{
@@ -73,6 +122,9 @@ func TestUserService_Create_Success(t *testing.T) {
 
 	// Mock successful creation
 	mockRepo.On("Create", ctx, mock.AnythingOfType("*entity.User")).Return(nil)
+	mockAudit.On("Record", ctx, mock.MatchedBy(func(entry audit.Entry) bool {
+		return entry.Action == audit.ActionUserCreate && entry.After["password"] != nil
+	})).Return(nil)
 
 	result, err := userService.Create(ctx, req)
 
@@ -81,12 +133,14 @@ func TestUserService_Create_Success(t *testing.T) {
 	assert.Equal(t, req.Name, result.Name)
 	assert.Equal(t, req.Email, result.Email)
 	mockRepo.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
 }
 
 func TestUserService_Create_EmailExists(t *testing.T) {
 	logger.Init("info")
 	mockRepo := new(MockUserRepository)
-	userService := serviceimpl.NewUserService(mockRepo, nil)
+	mockAudit := new(MockAuditService)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewNoop(), testCacheConfig, nil, passthroughTransactor{}, mockAudit)
 
 	ctx := context.Background()
 	req := &dto.CreateUserRequest{
@@ -110,3 +164,152 @@ func TestUserService_Create_EmailExists(t *testing.T) {
 	assert.Contains(t, err.Error(), "Email already exists")
 	mockRepo.AssertExpectations(t)
 }
+
+func TestUserService_Update_EmailChangeIsPending(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewNoop(), testCacheConfig, nil, passthroughTransactor{}, mockAudit)
+
+	// Users may change their own address
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 1})
+	newEmail := "john.doe@example.com"
+	user := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRepo.On("GetByEmail", ctx, newEmail).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Update", ctx, user).Return(nil)
+	mockAudit.On("Record", ctx, mock.MatchedBy(func(entry audit.Entry) bool {
+		return entry.Action == audit.ActionUserUpdate && entry.After["pending_email"] == newEmail
+	})).Return(nil)
+
+	result, err := userService.Update(ctx, user.ID, &dto.UpdateUserRequest{Email: &newEmail})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "john@example.com", result.Email)
+	assert.Equal(t, newEmail, result.PendingEmail)
+	mockRepo.AssertExpectations(t)
+	mockAudit.AssertExpectations(t)
+}
+
+func TestUserService_Update_EmailInUse(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewNoop(), testCacheConfig, nil, passthroughTransactor{}, mockAudit)
+
+	// Users may change their own address
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 1})
+	takenEmail := "jane@example.com"
+	user := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRepo.On("GetByEmail", ctx, takenEmail).Return(&entity.User{ID: 2, Email: takenEmail}, nil)
+
+	result, err := userService.Update(ctx, user.ID, &dto.UpdateUserRequest{Email: &takenEmail})
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusConflict, appErr.Code)
+	mockRepo.AssertNotCalled(t, "Update", ctx, user)
+}
+
+func TestUserService_Update_OtherUsersEmailNeedsAdmin(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewNoop(), testCacheConfig, nil, passthroughTransactor{}, mockAudit)
+
+	newEmail := "attacker@example.com"
+	victim := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com", Role: entity.RoleUser}
+	// The token still says admin, but the account has been demoted
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 2, Role: entity.RoleAdmin})
+	mockRepo.On("GetByID", ctx, uint(2)).Return(&entity.User{ID: 2, Role: entity.RoleUser}, nil)
+
+	result, err := userService.Update(ctx, victim.ID, &dto.UpdateUserRequest{Email: &newEmail})
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, appErr.Code)
+	mockRepo.AssertNotCalled(t, "GetByID", ctx, victim.ID)
+	mockRepo.AssertNotCalled(t, "Update", ctx, mock.Anything)
+}
+
+func TestUserService_Update_EmailChangeNeedsPrincipal(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewNoop(), testCacheConfig, nil, passthroughTransactor{}, mockAudit)
+
+	newEmail := "attacker@example.com"
+	result, err := userService.Update(context.Background(), 1, &dto.UpdateUserRequest{Email: &newEmail})
+
+	assert.Nil(t, result)
+	appErr, ok := err.(*errors.AppError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, appErr.Code)
+	mockRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+}
+
+func TestUserService_Update_SystemPrincipalCanChangeEmail(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewNoop(), testCacheConfig, nil, passthroughTransactor{}, mockAudit)
+
+	newEmail := "john.doe@example.com"
+	user := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+	ctx := auth.WithPrincipal(context.Background(), auth.SystemPrincipal("cli"))
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRepo.On("GetByEmail", ctx, newEmail).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Update", ctx, user).Return(nil)
+	mockAudit.On("Record", ctx, mock.Anything).Return(nil)
+
+	result, err := userService.Update(ctx, user.ID, &dto.UpdateUserRequest{Email: &newEmail})
+
+	assert.NoError(t, err)
+	assert.Equal(t, newEmail, result.PendingEmail)
+}
+
+func TestUserService_Update_AdminCanChangeOtherUsersEmail(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewNoop(), testCacheConfig, nil, passthroughTransactor{}, mockAudit)
+
+	newEmail := "john.doe@example.com"
+	user := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+	ctx := auth.WithPrincipal(context.Background(), &auth.Principal{UserID: 2, Role: entity.RoleAdmin})
+	mockRepo.On("GetByID", ctx, uint(2)).Return(&entity.User{ID: 2, Role: entity.RoleAdmin}, nil)
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRepo.On("GetByEmail", ctx, newEmail).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Update", ctx, user).Return(nil)
+	mockAudit.On("Record", ctx, mock.Anything).Return(nil)
+
+	result, err := userService.Update(ctx, user.ID, &dto.UpdateUserRequest{Email: &newEmail})
+
+	assert.NoError(t, err)
+	assert.Equal(t, newEmail, result.PendingEmail)
+}
+
+func TestUserService_Update_AuditFailureAbortsUpdate(t *testing.T) {
+	logger.Init("silent")
+	mockRepo := new(MockUserRepository)
+	mockAudit := new(MockAuditService)
+	userService := serviceimpl.NewUserService(mockRepo, cache.NewNoop(), testCacheConfig, nil, passthroughTransactor{}, mockAudit)
+
+	ctx := context.Background()
+	name := "Johnny"
+	user := &entity.User{ID: 1, Name: "John Doe", Email: "john@example.com"}
+
+	mockRepo.On("GetByID", ctx, user.ID).Return(user, nil)
+	mockRepo.On("Update", ctx, user).Return(nil)
+	mockAudit.On("Record", ctx, mock.Anything).Return(gorm.ErrInvalidTransaction)
+
+	result, err := userService.Update(ctx, user.ID, &dto.UpdateUserRequest{Name: &name})
+
+	assert.Nil(t, result)
+	assert.Error(t, err)
+}